@@ -3,29 +3,29 @@ package abci
 import (
 	"context"
 	"errors"
-	tmhttp "github.com/tendermint/tendermint/rpc/client/http"
 	"simple-exporter/types"
+	"time"
 )
 
+// QueryTimeout is the timeout of the ABCI queries
+var QueryTimeout = 10 * time.Second
+
+// Client performs the ABCI queries through a node endpoint (RPC or gRPC)
+type Client interface {
+	// Query performs the query, returning an error only if the endpoint failed to answer.
+	// The query errors are reported in the response Log
+	Query(ctx context.Context, path string, data []byte) (*types.ResultABCIQuery, error)
+}
+
 // ABCIQuery Perform an ABCI query
-func ABCIQuery(ctx context.Context, client *tmhttp.HTTP, path string, data types.HexBytes) (*types.ResultABCIQuery, error) {
+func ABCIQuery(ctx context.Context, client Client, path string, data types.HexBytes) (*types.ResultABCIQuery, error) {
 	if client == nil {
-		return nil, errors.New("RPC Client not available")
+		return &types.ResultABCIQuery{}, errors.New("ABCI Client not available")
 	}
-	response, err := client.ABCIQuery(ctx, path, []byte(data))
+	response, err := client.Query(ctx, path, data)
 	if err != nil {
-		return nil, err
+		// empty response, so that its Log can be checked
+		return &types.ResultABCIQuery{}, err
 	}
-	return &types.ResultABCIQuery{
-		Response: types.ResponseQuery{
-			Code:      response.Response.Code,
-			Log:       response.Response.Log,
-			Info:      response.Response.Info,
-			Index:     response.Response.Index,
-			Key:       response.Response.Key,
-			Value:     response.Response.Value,
-			Height:    response.Response.Height,
-			Codespace: response.Response.Codespace,
-		},
-	}, err
+	return response, nil
 }
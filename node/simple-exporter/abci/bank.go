@@ -0,0 +1,113 @@
+package abci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	bankTypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+// GetAllBalances queries the ABCI endpoint to get all the balances of an account
+func GetAllBalances(client Client, address string) (types.Coins, error) {
+	var nextKey []byte
+	var done = false
+
+	var balances types.Coins
+
+	for done == false {
+		// prepare the request data and pagination
+		var request = bankTypes.QueryAllBalancesRequest{
+			Address: address,
+			Pagination: &query.PageRequest{
+				Key:   nextKey,
+				Limit: 200,
+			},
+		}
+		data, _ := request.Marshal()
+
+		var ctx = context.Background()
+		bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+
+		// perform the ABCI query
+		raw, err := ABCIQuery(bctx, client, "/cosmos.bank.v1beta1.Query/AllBalances", data)
+		defer cancel()
+		if err != nil || raw.Response.Log != "" {
+			if raw.Response.Log != "" {
+				return nil, errors.New(fmt.Sprintf("Invalid Response Log: %s", raw.Response.Log))
+			}
+			return nil, err
+		}
+
+		// decode the response
+		var response bankTypes.QueryAllBalancesResponse
+		err = response.Unmarshal(raw.Response.GetValue())
+		if err != nil {
+			return nil, err
+		}
+
+		// handle the new pagination
+		if response.Pagination != nil && response.Pagination.NextKey != nil {
+			nextKey = response.Pagination.NextKey
+		} else {
+			done = true
+		}
+
+		balances = append(balances, response.Balances...)
+	}
+
+	return balances, nil
+}
+
+// GetSpendableBalances queries the ABCI endpoint to get the spendable balances of an account (without the vesting ones)
+// Note: Available since Cosmos-Sdk v0.46
+func GetSpendableBalances(client Client, address string) (types.Coins, error) {
+	var nextKey []byte
+	var done = false
+
+	var balances types.Coins
+
+	for done == false {
+		// prepare the request data and pagination
+		var request = bankTypes.QuerySpendableBalancesRequest{
+			Address: address,
+			Pagination: &query.PageRequest{
+				Key:   nextKey,
+				Limit: 200,
+			},
+		}
+		data, _ := request.Marshal()
+
+		var ctx = context.Background()
+		bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+
+		// perform the ABCI query
+		raw, err := ABCIQuery(bctx, client, "/cosmos.bank.v1beta1.Query/SpendableBalances", data)
+		defer cancel()
+		if err != nil || raw.Response.Log != "" {
+			if raw.Response.Log != "" {
+				return nil, errors.New(fmt.Sprintf("Invalid Response Log: %s", raw.Response.Log))
+			}
+			return nil, err
+		}
+
+		// decode the response
+		var response bankTypes.QuerySpendableBalancesResponse
+		err = response.Unmarshal(raw.Response.GetValue())
+		if err != nil {
+			return nil, err
+		}
+
+		// handle the new pagination
+		if response.Pagination != nil && response.Pagination.NextKey != nil {
+			nextKey = response.Pagination.NextKey
+		} else {
+			done = true
+		}
+
+		balances = append(balances, response.Balances...)
+	}
+
+	return balances, nil
+}
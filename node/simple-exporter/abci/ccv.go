@@ -0,0 +1,120 @@
+package abci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The ICS provider types are not part of the Cosmos-Sdk, their few used fields are encoded by hand
+
+// ConsumerChain identifies a consumer chain on the ICS provider chain
+type ConsumerChain struct {
+	ChainID    string
+	ConsumerID string // consumer id since ICS v6, the consumer chains were identified by their chain id before
+}
+
+// appendTo appends the consumer chain identifier to the request, at the given field number
+func (c ConsumerChain) appendTo(data []byte, number protowire.Number) []byte {
+	if c.ConsumerID != "" {
+		return appendString(data, number, c.ConsumerID)
+	}
+	return appendString(data, number, c.ChainID)
+}
+
+// GetValidatorConsumerAddr queries the ICS provider ABCI endpoint to get the consensus address assigned by the validator to the consumer chain.
+// It returns an empty address if the validator has not assigned a consumer key, so it uses its provider one
+func GetValidatorConsumerAddr(client Client, consumer ConsumerChain, providerValcons string) (string, error) {
+
+	// prepare the request data, the fields order changed with the consumer ids (ICS v6)
+	var data []byte
+	if consumer.ConsumerID != "" {
+		data = appendString(data, 1, providerValcons)
+		data = consumer.appendTo(data, 2)
+	} else {
+		data = consumer.appendTo(data, 1)
+		data = appendString(data, 2, providerValcons)
+	}
+
+	var ctx = context.Background()
+	bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+
+	// perform the ABCI query
+	raw, err := ABCIQuery(bctx, client, "/interchain_security.ccv.provider.v1.Query/QueryValidatorConsumerAddr", data)
+	defer cancel()
+	if err != nil || raw.Response.Log != "" {
+		if raw.Response.Log != "" {
+			return "", errors.New(fmt.Sprintf("Invalid Response Log: %s", raw.Response.Log))
+		}
+		return "", err
+	}
+
+	// decode the response, holding only the consumer_address field
+	addresses, err := consumeStrings(raw.Response.GetValue(), 1)
+	if err != nil {
+		return "", err
+	}
+	if len(addresses) == 0 {
+		return "", nil
+	}
+	return addresses[0], nil
+
+}
+
+// GetConsumerOptedInValidators queries the ICS provider ABCI endpoint to get the provider consensus addresses
+// of the validators opted in to validate the consumer chain (partial set security, since ICS v5)
+func GetConsumerOptedInValidators(client Client, consumer ConsumerChain) ([]string, error) {
+
+	// prepare the request data
+	var data = consumer.appendTo(nil, 1)
+
+	var ctx = context.Background()
+	bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+
+	// perform the ABCI query
+	raw, err := ABCIQuery(bctx, client, "/interchain_security.ccv.provider.v1.Query/QueryConsumerChainOptedInValidators", data)
+	defer cancel()
+	if err != nil || raw.Response.Log != "" {
+		if raw.Response.Log != "" {
+			return nil, errors.New(fmt.Sprintf("Invalid Response Log: %s", raw.Response.Log))
+		}
+		return nil, err
+	}
+
+	// decode the response, holding only the validators_provider_addresses field
+	return consumeStrings(raw.Response.GetValue(), 1)
+
+}
+
+func appendString(data []byte, number protowire.Number, value string) []byte {
+	data = protowire.AppendTag(data, number, protowire.BytesType)
+	return protowire.AppendString(data, value)
+}
+
+// consumeStrings decodes the values of a string field, skipping the other fields
+func consumeStrings(data []byte, wanted protowire.Number) ([]string, error) {
+	var values []string
+	for len(data) > 0 {
+		number, wireType, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		data = data[n:]
+		if number == wanted && wireType == protowire.BytesType {
+			value, n := protowire.ConsumeString(data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			values = append(values, value)
+			data = data[n:]
+			continue
+		}
+		n = protowire.ConsumeFieldValue(number, wireType, data)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		data = data[n:]
+	}
+	return values, nil
+}
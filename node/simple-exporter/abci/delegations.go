@@ -0,0 +1,153 @@
+package abci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	stakingTypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"strings"
+)
+
+// GetValidatorDelegations queries the ABCI endpoint to get all the delegations of a Validator
+func GetValidatorDelegations(client Client, validatorAddr string) (stakingTypes.DelegationResponses, error) {
+	var nextKey []byte
+	var done = false
+
+	var delegations stakingTypes.DelegationResponses
+
+	for done == false {
+		// prepare the request data and pagination
+		var request = stakingTypes.QueryValidatorDelegationsRequest{
+			ValidatorAddr: validatorAddr,
+			Pagination: &query.PageRequest{
+				Key:   nextKey,
+				Limit: 1000,
+			},
+		}
+		data, _ := request.Marshal()
+
+		var ctx = context.Background()
+		bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+
+		// perform the ABCI query
+		raw, err := ABCIQuery(bctx, client, "/cosmos.staking.v1beta1.Query/ValidatorDelegations", data)
+		defer cancel()
+		if err != nil || raw.Response.Log != "" {
+			if raw.Response.Log != "" {
+				return nil, errors.New(fmt.Sprintf("Invalid Response Log: %s", raw.Response.Log))
+			}
+			return nil, err
+		}
+
+		// decode the response
+		var response stakingTypes.QueryValidatorDelegationsResponse
+		err = response.Unmarshal(raw.Response.GetValue())
+		if err != nil {
+			return nil, err
+		}
+
+		// handle the new pagination
+		if response.Pagination != nil && response.Pagination.NextKey != nil {
+			nextKey = response.Pagination.NextKey
+		} else {
+			done = true
+		}
+
+		delegations = append(delegations, response.DelegationResponses...)
+	}
+
+	return delegations, nil
+}
+
+// GetValidatorUnbondingDelegations queries the ABCI endpoint to get all the unbonding delegations of a Validator
+func GetValidatorUnbondingDelegations(client Client, validatorAddr string) ([]stakingTypes.UnbondingDelegation, error) {
+	var nextKey []byte
+	var done = false
+
+	var unbondings []stakingTypes.UnbondingDelegation
+
+	for done == false {
+		// prepare the request data and pagination
+		var request = stakingTypes.QueryValidatorUnbondingDelegationsRequest{
+			ValidatorAddr: validatorAddr,
+			Pagination: &query.PageRequest{
+				Key:   nextKey,
+				Limit: 1000,
+			},
+		}
+		data, _ := request.Marshal()
+
+		var ctx = context.Background()
+		bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+
+		// perform the ABCI query
+		raw, err := ABCIQuery(bctx, client, "/cosmos.staking.v1beta1.Query/ValidatorUnbondingDelegations", data)
+		defer cancel()
+		if err != nil || raw.Response.Log != "" {
+			if raw.Response.Log != "" {
+				return nil, errors.New(fmt.Sprintf("Invalid Response Log: %s", raw.Response.Log))
+			}
+			return nil, err
+		}
+
+		// decode the response
+		var response stakingTypes.QueryValidatorUnbondingDelegationsResponse
+		err = response.Unmarshal(raw.Response.GetValue())
+		if err != nil {
+			return nil, err
+		}
+
+		// handle the new pagination
+		if response.Pagination != nil && response.Pagination.NextKey != nil {
+			nextKey = response.Pagination.NextKey
+		} else {
+			done = true
+		}
+
+		unbondings = append(unbondings, response.UnbondingResponses...)
+	}
+
+	return unbondings, nil
+}
+
+// GetDelegation queries the ABCI endpoint to get the tokens delegated by the delegator to the Validator, zero if not delegated
+func GetDelegation(client Client, delegatorAddr string, validatorAddr string) (types.Int, error) {
+	// prepare the request data
+	var request = stakingTypes.QueryDelegationRequest{
+		DelegatorAddr: delegatorAddr,
+		ValidatorAddr: validatorAddr,
+	}
+	data, _ := request.Marshal()
+
+	var ctx = context.Background()
+	bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+
+	// perform the ABCI query
+	raw, err := ABCIQuery(bctx, client, "/cosmos.staking.v1beta1.Query/Delegation", data)
+	defer cancel()
+	if err != nil {
+		return types.Int{}, err
+	}
+	if raw.Response.Log != "" {
+		// the delegation is removed once fully undelegated
+		if strings.Contains(raw.Response.Log, "not found for validator") {
+			return types.ZeroInt(), nil
+		}
+		return types.Int{}, errors.New(fmt.Sprintf("Invalid Response Log: %s", raw.Response.Log))
+	}
+
+	// decode the response
+	var response stakingTypes.QueryDelegationResponse
+	err = response.Unmarshal(raw.Response.GetValue())
+	if err != nil {
+		return types.Int{}, err
+	}
+	if response.DelegationResponse == nil {
+		return types.ZeroInt(), nil
+	}
+
+	// return the wanted data
+	return response.DelegationResponse.Balance.Amount, nil
+}
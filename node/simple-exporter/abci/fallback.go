@@ -0,0 +1,38 @@
+package abci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"simple-exporter/types"
+	"strings"
+)
+
+// FallbackClient performs the ABCI queries through the first client, falling back to the next ones if its endpoint fails
+type FallbackClient struct {
+	clients []Client
+}
+
+func NewFallbackClient(clients ...Client) *FallbackClient {
+	return &FallbackClient{clients: clients}
+}
+
+func (c *FallbackClient) Query(ctx context.Context, path string, data []byte) (*types.ResultABCIQuery, error) {
+	var errs []string
+	for _, client := range c.clients {
+		response, err := queryAttempt(ctx, client, path, data)
+		if err == nil {
+			return response, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	return nil, errors.New(fmt.Sprintf("all the query endpoints failed: %s", strings.Join(errs, "; ")))
+}
+
+// queryAttempt performs the query through a single client with its own timeout,
+// the caller deadline being possibly spent by a previous endpoint timing out
+func queryAttempt(ctx context.Context, client Client, path string, data []byte) (*types.ResultABCIQuery, error) {
+	actx, cancel := context.WithTimeout(context.WithoutCancel(ctx), QueryTimeout)
+	defer cancel()
+	return client.Query(actx, path, data)
+}
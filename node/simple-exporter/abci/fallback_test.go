@@ -0,0 +1,145 @@
+package abci
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"simple-exporter/types"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClient answers the queries with the given response or endpoint error, counting them.
+// As a real endpoint, it fails the queries with a done context
+type fakeClient struct {
+	response *types.ResultABCIQuery
+	err      error
+	queries  int
+}
+
+func (c *fakeClient) Query(ctx context.Context, _ string, _ []byte) (*types.ResultABCIQuery, error) {
+	c.queries++
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return c.response, c.err
+}
+
+func TestFallbackClientQuery(t *testing.T) {
+	var ok = &types.ResultABCIQuery{Response: types.ResponseQuery{Value: []byte("ok")}}
+	var notFound = &types.ResultABCIQuery{Response: types.ResponseQuery{Code: uint32(codes.NotFound), Log: "not found"}}
+	var unavailable = status.Error(codes.Unavailable, "connection refused")
+
+	var tests = []struct {
+		name     string
+		clients  []*fakeClient
+		want     *types.ResultABCIQuery
+		wantErr  string
+		wantUsed []int // queries count of each client
+	}{
+		{
+			name:     "first client answers",
+			clients:  []*fakeClient{{response: ok}, {response: notFound}},
+			want:     ok,
+			wantUsed: []int{1, 0},
+		},
+		{
+			name:     "endpoint error falls back in order",
+			clients:  []*fakeClient{{err: unavailable}, {response: ok}, {response: notFound}},
+			want:     ok,
+			wantUsed: []int{1, 1, 0},
+		},
+		{
+			name:     "application error doesn't fall back",
+			clients:  []*fakeClient{{response: notFound}, {response: ok}},
+			want:     notFound,
+			wantUsed: []int{1, 0},
+		},
+		{
+			name:     "all endpoints fail",
+			clients:  []*fakeClient{{err: unavailable}, {err: errors.New("timeout")}},
+			wantErr:  "all the query endpoints failed: rpc error: code = Unavailable desc = connection refused; timeout",
+			wantUsed: []int{1, 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var clients []Client
+			for _, client := range test.clients {
+				clients = append(clients, client)
+			}
+			response, err := NewFallbackClient(clients...).Query(context.Background(), "/cosmos.slashing.v1beta1.Query/Params", nil)
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Errorf("got error %v, want '%s'", err, test.wantErr)
+				}
+			} else if err != nil || response != test.want {
+				t.Errorf("got response %+v (%v), want %+v", response, err, test.want)
+			}
+			for i, client := range test.clients {
+				if client.queries != test.wantUsed[i] {
+					t.Errorf("client %d got %d queries, want %d", i, client.queries, test.wantUsed[i])
+				}
+			}
+		})
+	}
+}
+
+// blockingClient blocks the queries until their context is done, as an endpoint not answering
+type blockingClient struct{}
+
+func (c *blockingClient) Query(ctx context.Context, _ string, _ []byte) (*types.ResultABCIQuery, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestFallbackClientTimeout(t *testing.T) {
+	var timeout = QueryTimeout
+	QueryTimeout = 50 * time.Millisecond
+	defer func() { QueryTimeout = timeout }()
+
+	// the caller deadline is spent by the blocking endpoint, the next one getting its own timeout
+	ctx, cancel := context.WithTimeout(context.Background(), QueryTimeout/2)
+	defer cancel()
+	var next = &fakeClient{response: &types.ResultABCIQuery{Response: types.ResponseQuery{Value: []byte("ok")}}}
+	response, err := NewFallbackClient(&blockingClient{}, next).Query(ctx, "/cosmos.slashing.v1beta1.Query/Params", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Err() == nil || next.queries != 1 || string(response.Response.Value) != "ok" {
+		t.Errorf("got response %+v after %d fallback queries (caller context error %v)", response.Response, next.queries, ctx.Err())
+	}
+
+	// the blocking endpoints still fail after their own timeout
+	_, err = NewFallbackClient(&blockingClient{}, &blockingClient{}).Query(context.Background(), "/cosmos.slashing.v1beta1.Query/Params", nil)
+	if err == nil || err.Error() != "all the query endpoints failed: context deadline exceeded; context deadline exceeded" {
+		t.Errorf("got error %v, want both endpoints timing out", err)
+	}
+}
+
+func TestFallbackClientGRPC(t *testing.T) {
+	// the gRPC endpoint doesn't serve the gov v1 queries, falling back to the next client
+	var address = newTestGRPCServer(t, nil)
+	grpcClient, err := NewGRPCClient(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer grpcClient.Close()
+	var next = &fakeClient{response: &types.ResultABCIQuery{Response: types.ResponseQuery{Value: []byte("ok")}}}
+
+	response, err := NewFallbackClient(grpcClient, next).Query(context.Background(), "/cosmos.gov.v1.Query/Proposals", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.queries != 1 || string(response.Response.Value) != "ok" {
+		t.Errorf("got response %+v after %d fallback queries", response.Response, next.queries)
+	}
+
+	// without other clients the gRPC error is reported
+	_, err = NewFallbackClient(grpcClient).Query(context.Background(), "/cosmos.gov.v1.Query/Proposals", nil)
+	if err == nil || !strings.Contains(err.Error(), "Unimplemented") {
+		t.Errorf("got error %v, want the gRPC Unimplemented error", err)
+	}
+}
@@ -0,0 +1,214 @@
+package abci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	govV1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	govV1beta1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	"simple-exporter/types"
+	"strings"
+	"time"
+)
+
+// GovProposal holds the info of a governance proposal, common to the v1 and v1beta1 gov modules
+type GovProposal struct {
+	ID            uint64
+	VotingEndTime time.Time
+}
+
+// GetVotingProposals queries the ABCI endpoint to get the governance proposals in voting period.
+// It falls back to the v1beta1 gov module on chains without the v1 one (before Cosmos-Sdk v0.46), queried directly if legacy
+func GetVotingProposals(client Client, legacy bool) ([]GovProposal, error) {
+	if legacy {
+		return getVotingProposalsV1beta1(client)
+	}
+	proposals, err := getVotingProposalsV1(client)
+	if err == nil {
+		return proposals, nil
+	}
+	return getVotingProposalsV1beta1(client)
+}
+
+// GetGovVote queries the ABCI endpoint to get the vote options of the voter on a proposal.
+// It returns nil if the voter has not voted yet, the v1beta1 gov module is queried directly if legacy
+func GetGovVote(client Client, proposalID uint64, voter string, legacy bool) ([]govV1.VoteOption, error) {
+	if legacy {
+		return getGovVoteV1beta1(client, proposalID, voter)
+	}
+	options, err := getGovVoteV1(client, proposalID, voter)
+	if err == nil {
+		return options, nil
+	}
+	return getGovVoteV1beta1(client, proposalID, voter)
+}
+
+func getVotingProposalsV1(client Client) ([]GovProposal, error) {
+	// prepare the request data, the proposals in voting period are few
+	var request = govV1.QueryProposalsRequest{
+		ProposalStatus: govV1.StatusVotingPeriod,
+		Pagination: &query.PageRequest{
+			Limit: 100,
+		},
+	}
+	data, _ := request.Marshal()
+
+	var ctx = context.Background()
+	bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+
+	// perform the ABCI query
+	raw, err := ABCIQuery(bctx, client, "/cosmos.gov.v1.Query/Proposals", data)
+	defer cancel()
+	if err != nil || raw.Response.Log != "" {
+		if raw.Response.Log != "" {
+			return nil, errors.New(fmt.Sprintf("Invalid Response Log: %s", raw.Response.Log))
+		}
+		return nil, err
+	}
+
+	// decode the response
+	var response govV1.QueryProposalsResponse
+	err = response.Unmarshal(raw.Response.GetValue())
+	if err != nil {
+		return nil, err
+	}
+
+	// extract only the wanted data
+	var proposals []GovProposal
+	for _, proposal := range response.Proposals {
+		var votingEndTime time.Time
+		if proposal.VotingEndTime != nil {
+			votingEndTime = *proposal.VotingEndTime
+		}
+		proposals = append(proposals, GovProposal{ID: proposal.Id, VotingEndTime: votingEndTime})
+	}
+	return proposals, nil
+}
+
+func getVotingProposalsV1beta1(client Client) ([]GovProposal, error) {
+	// prepare the request data, the proposals in voting period are few
+	var request = govV1beta1.QueryProposalsRequest{
+		ProposalStatus: govV1beta1.StatusVotingPeriod,
+		Pagination: &query.PageRequest{
+			Limit: 100,
+		},
+	}
+	data, _ := request.Marshal()
+
+	var ctx = context.Background()
+	bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+
+	// perform the ABCI query
+	raw, err := ABCIQuery(bctx, client, "/cosmos.gov.v1beta1.Query/Proposals", data)
+	defer cancel()
+	if err != nil || raw.Response.Log != "" {
+		if raw.Response.Log != "" {
+			return nil, errors.New(fmt.Sprintf("Invalid Response Log: %s", raw.Response.Log))
+		}
+		return nil, err
+	}
+
+	// decode the response
+	var response govV1beta1.QueryProposalsResponse
+	err = response.Unmarshal(raw.Response.GetValue())
+	if err != nil {
+		return nil, err
+	}
+
+	// extract only the wanted data
+	var proposals []GovProposal
+	for _, proposal := range response.Proposals {
+		proposals = append(proposals, GovProposal{ID: proposal.ProposalId, VotingEndTime: proposal.VotingEndTime})
+	}
+	return proposals, nil
+}
+
+func getGovVoteV1(client Client, proposalID uint64, voter string) ([]govV1.VoteOption, error) {
+	// prepare the request data
+	var request = govV1.QueryVoteRequest{
+		ProposalId: proposalID,
+		Voter:      voter,
+	}
+	data, _ := request.Marshal()
+
+	var ctx = context.Background()
+	bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+
+	// perform the ABCI query
+	raw, err := ABCIQuery(bctx, client, "/cosmos.gov.v1.Query/Vote", data)
+	defer cancel()
+	if err != nil || raw.Response.Log != "" {
+		if isVoteNotFound(raw) {
+			return nil, nil
+		}
+		if raw.Response.Log != "" {
+			return nil, errors.New(fmt.Sprintf("Invalid Response Log: %s", raw.Response.Log))
+		}
+		return nil, err
+	}
+
+	// decode the response
+	var response govV1.QueryVoteResponse
+	err = response.Unmarshal(raw.Response.GetValue())
+	if err != nil {
+		return nil, err
+	}
+
+	// extract only the wanted data
+	var options []govV1.VoteOption
+	if response.Vote != nil {
+		for _, option := range response.Vote.Options {
+			options = append(options, option.Option)
+		}
+	}
+	return options, nil
+}
+
+func getGovVoteV1beta1(client Client, proposalID uint64, voter string) ([]govV1.VoteOption, error) {
+	// prepare the request data
+	var request = govV1beta1.QueryVoteRequest{
+		ProposalId: proposalID,
+		Voter:      voter,
+	}
+	data, _ := request.Marshal()
+
+	var ctx = context.Background()
+	bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+
+	// perform the ABCI query
+	raw, err := ABCIQuery(bctx, client, "/cosmos.gov.v1beta1.Query/Vote", data)
+	defer cancel()
+	if err != nil || raw.Response.Log != "" {
+		if isVoteNotFound(raw) {
+			return nil, nil
+		}
+		if raw.Response.Log != "" {
+			return nil, errors.New(fmt.Sprintf("Invalid Response Log: %s", raw.Response.Log))
+		}
+		return nil, err
+	}
+
+	// decode the response
+	var response govV1beta1.QueryVoteResponse
+	err = response.Unmarshal(raw.Response.GetValue())
+	if err != nil {
+		return nil, err
+	}
+
+	// extract only the wanted data, the v1beta1 options have the same values of the v1 ones
+	var options []govV1.VoteOption
+	for _, option := range response.Vote.Options {
+		options = append(options, govV1.VoteOption(option.Option))
+	}
+	// the deprecated single option of the old votes
+	if len(options) == 0 && response.Vote.Option != govV1beta1.OptionEmpty {
+		options = append(options, govV1.VoteOption(response.Vote.Option))
+	}
+	return options, nil
+}
+
+// isVoteNotFound checks if the vote query failed because the voter has not voted
+func isVoteNotFound(raw *types.ResultABCIQuery) bool {
+	return raw != nil && strings.Contains(raw.Response.Log, "not found for proposal")
+}
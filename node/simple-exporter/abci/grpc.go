@@ -0,0 +1,86 @@
+package abci
+
+import (
+	"context"
+	"crypto/tls"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"simple-exporter/types"
+	"strings"
+)
+
+// GRPCClient performs the ABCI queries through the node gRPC endpoint, using the same methods of the Cosmos-Sdk query clients
+type GRPCClient struct {
+	conn *grpc.ClientConn
+}
+
+// NewGRPCClient connects to the gRPC endpoint (ex. localhost:9090), using TLS if prefixed by https://
+func NewGRPCClient(endpoint string) (*GRPCClient, error) {
+	var transportCredentials = insecure.NewCredentials()
+	if strings.HasPrefix(endpoint, "https://") {
+		transportCredentials = credentials.NewTLS(&tls.Config{})
+	}
+	var address = strings.TrimPrefix(strings.TrimPrefix(endpoint, "https://"), "http://")
+
+	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		return nil, err
+	}
+	return &GRPCClient{conn: conn}, nil
+}
+
+func (c *GRPCClient) Query(ctx context.Context, path string, data []byte) (*types.ResultABCIQuery, error) {
+	// the ABCI query paths are the gRPC methods, the data is already marshalled
+	var response []byte
+	err := c.conn.Invoke(ctx, path, &data, &response, grpc.ForceCodec(rawCodec{}))
+	if err != nil {
+		grpcStatus, ok := status.FromError(err)
+		if !ok || isEndpointError(grpcStatus.Code()) {
+			return nil, err
+		}
+		// the query errors are reported as the ABCI ones
+		return &types.ResultABCIQuery{
+			Response: types.ResponseQuery{
+				Code: uint32(grpcStatus.Code()),
+				Log:  grpcStatus.Message(),
+			},
+		}, nil
+	}
+	return &types.ResultABCIQuery{
+		Response: types.ResponseQuery{
+			Value: response,
+		},
+	}, nil
+}
+
+func (c *GRPCClient) Close() error {
+	return c.conn.Close()
+}
+
+// isEndpointError checks if the gRPC error code is caused by the endpoint and not by the query
+func isEndpointError(code codes.Code) bool {
+	switch code {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled, codes.Unimplemented, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
+
+// rawCodec sends and receives the already marshalled protobuf messages
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	return *v.(*[]byte), nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	*v.(*[]byte) = append([]byte{}, data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
@@ -0,0 +1,164 @@
+package abci
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	slashingTypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net"
+	"testing"
+)
+
+// newTestGRPCServer serves the given query handlers like a node gRPC endpoint, the other methods being unimplemented
+func newTestGRPCServer(t *testing.T, handlers map[string]func(request []byte) ([]byte, error)) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var server = grpc.NewServer(grpc.ForceServerCodec(rawCodec{}), grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+		method, _ := grpc.MethodFromServerStream(stream)
+		handler, ok := handlers[method]
+		if !ok {
+			return status.Errorf(codes.Unimplemented, "unknown method %s", method)
+		}
+		var request []byte
+		err := stream.RecvMsg(&request)
+		if err != nil {
+			return err
+		}
+		response, err := handler(request)
+		if err != nil {
+			return err
+		}
+		return stream.SendMsg(&response)
+	}))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func TestRawCodec(t *testing.T) {
+	var codec = rawCodec{}
+	var message = []byte{0x0a, 0x03, 'a', 'b', 'c'}
+	data, err := codec.Marshal(&message)
+	if err != nil || !bytes.Equal(data, message) {
+		t.Fatalf("got marshalled %x (%v), want %x", data, err, message)
+	}
+
+	var decoded []byte
+	err = codec.Unmarshal(data, &decoded)
+	if err != nil || !bytes.Equal(decoded, message) {
+		t.Fatalf("got unmarshalled %x (%v), want %x", decoded, err, message)
+	}
+	// the received buffer may be reused by gRPC, the message is copied
+	data[0] = 0xff
+	if decoded[0] != 0x0a {
+		t.Error("the unmarshalled message shares the received buffer")
+	}
+	if codec.Name() != "proto" {
+		t.Errorf("got codec name '%s', want the proto content subtype", codec.Name())
+	}
+}
+
+func TestIsEndpointError(t *testing.T) {
+	var tests = []struct {
+		code codes.Code
+		want bool
+	}{
+		{codes.Unavailable, true},
+		{codes.Unimplemented, true},
+		{codes.DeadlineExceeded, true},
+		{codes.Canceled, true},
+		{codes.ResourceExhausted, true},
+		{codes.InvalidArgument, false},
+		{codes.NotFound, false},
+		{codes.Internal, false},
+		{codes.Unknown, false},
+	}
+	for _, test := range tests {
+		if got := isEndpointError(test.code); got != test.want {
+			t.Errorf("isEndpointError(%s) = %t, want %t", test.code, got, test.want)
+		}
+	}
+}
+
+func TestGRPCClientQuery(t *testing.T) {
+	var params = slashingTypes.Params{SignedBlocksWindow: 10000}
+	var address = newTestGRPCServer(t, map[string]func(request []byte) ([]byte, error){
+		"/cosmos.slashing.v1beta1.Query/Params": func(request []byte) ([]byte, error) {
+			return (&slashingTypes.QueryParamsResponse{Params: params}).Marshal()
+		},
+		"/cosmos.slashing.v1beta1.Query/SigningInfo": func(request []byte) ([]byte, error) {
+			// the request is received as marshalled by the query
+			var signingInfoRequest slashingTypes.QuerySigningInfoRequest
+			err := signingInfoRequest.Unmarshal(request)
+			if err != nil {
+				return nil, err
+			}
+			return nil, status.Errorf(codes.NotFound, "SigningInfo not found for validator %s", signingInfoRequest.ConsAddress)
+		},
+	})
+	client, err := NewGRPCClient(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// the responses are decoded like the ABCI ones
+	slashingParams, err := GetSlashingParams(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slashingParams.SignedBlocksWindow != params.SignedBlocksWindow {
+		t.Errorf("got slashing params %+v", slashingParams)
+	}
+
+	// the application errors are reported in the response log, not falling back to the other endpoints
+	response, err := client.Query(context.Background(), "/cosmos.slashing.v1beta1.Query/SigningInfo", mustMarshal(t, &slashingTypes.QuerySigningInfoRequest{ConsAddress: "cosmosvalcons1test"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Response.Code != uint32(codes.NotFound) || response.Response.Log != "SigningInfo not found for validator cosmosvalcons1test" {
+		t.Errorf("got response %+v", response.Response)
+	}
+
+	// the missing services are endpoint errors
+	_, err = client.Query(context.Background(), "/cosmos.gov.v1.Query/Proposals", nil)
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("got error %v, want %s", err, codes.Unimplemented)
+	}
+}
+
+func TestGRPCClientUnavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var address = listener.Addr().String()
+	_ = listener.Close()
+
+	client, err := NewGRPCClient(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	_, err = client.Query(context.Background(), "/cosmos.slashing.v1beta1.Query/Params", nil)
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("got error %v, want %s", err, codes.Unavailable)
+	}
+}
+
+func mustMarshal(t *testing.T, message interface{ Marshal() ([]byte, error) }) []byte {
+	t.Helper()
+	data, err := message.Marshal()
+	if err != nil {
+		t.Fatal(errors.New("cannot marshal the request: " + err.Error()))
+	}
+	return data
+}
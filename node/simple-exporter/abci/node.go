@@ -0,0 +1,43 @@
+package abci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+)
+
+// GetAppVersionInfo queries the ABCI endpoint to get the application build info, including the Cosmos-Sdk version (since v0.43)
+func GetAppVersionInfo(client Client) (*tmservice.VersionInfo, error) {
+
+	// prepare the request data
+	var request = tmservice.GetNodeInfoRequest{}
+	data, _ := request.Marshal()
+
+	var ctx = context.Background()
+	bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+
+	// perform the ABCI query
+	raw, err := ABCIQuery(bctx, client, "/cosmos.base.tendermint.v1beta1.Service/GetNodeInfo", data)
+	defer cancel()
+	if err != nil || raw.Response.Log != "" {
+		if raw.Response.Log != "" {
+			return nil, errors.New(fmt.Sprintf("Invalid Response Log: %s", raw.Response.Log))
+		}
+		return nil, err
+	}
+
+	// decode the response
+	var response tmservice.GetNodeInfoResponse
+	err = response.Unmarshal(raw.Response.GetValue())
+	if err != nil {
+		return nil, err
+	}
+	if response.ApplicationVersion == nil {
+		return nil, errors.New("application version not available")
+	}
+
+	// return the wanted data
+	return response.ApplicationVersion, nil
+
+}
@@ -11,12 +11,10 @@ import (
 	distributionTypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
 	slashingTypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
 	stakingTypes "github.com/cosmos/cosmos-sdk/x/staking/types"
-	"github.com/tendermint/tendermint/rpc/client/http"
-	"time"
 )
 
 // GetValidatorSigningInfo queries the ABCI endpoint to get the SigningInfo of a given Validator
-func GetValidatorSigningInfo(client *http.HTTP, validatorAddr string) (*slashingTypes.ValidatorSigningInfo, error) {
+func GetValidatorSigningInfo(client Client, validatorAddr string) (*slashingTypes.ValidatorSigningInfo, error) {
 
 	// prepare the request data
 	var request = slashingTypes.QuerySigningInfoRequest{
@@ -25,7 +23,7 @@ func GetValidatorSigningInfo(client *http.HTTP, validatorAddr string) (*slashing
 	data, _ := request.Marshal()
 
 	var ctx = context.Background()
-	bctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
 
 	// perform the ABCI query
 	raw, err := ABCIQuery(bctx, client, "/cosmos.slashing.v1beta1.Query/SigningInfo", data)
@@ -49,8 +47,40 @@ func GetValidatorSigningInfo(client *http.HTTP, validatorAddr string) (*slashing
 
 }
 
+// GetSlashingParams queries the ABCI endpoint to get the Slashing module params
+func GetSlashingParams(client Client) (*slashingTypes.Params, error) {
+
+	// prepare the request data
+	var request = slashingTypes.QueryParamsRequest{}
+	data, _ := request.Marshal()
+
+	var ctx = context.Background()
+	bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+
+	// perform the ABCI query
+	raw, err := ABCIQuery(bctx, client, "/cosmos.slashing.v1beta1.Query/Params", data)
+	defer cancel()
+	if err != nil || raw.Response.Log != "" {
+		if raw.Response.Log != "" {
+			return nil, errors.New(fmt.Sprintf("Invalid Response Log: %s", raw.Response.Log))
+		}
+		return nil, err
+	}
+
+	// decode the response
+	var response slashingTypes.QueryParamsResponse
+	err = response.Unmarshal(raw.Response.GetValue())
+	if err != nil {
+		return nil, err
+	}
+
+	// return the wanted data
+	return &response.Params, nil
+
+}
+
 // GetValidators queries the ABCI endpoint to get the Validators
-func GetValidators(client *http.HTTP) (*[]stakingTypes.Validator, error) {
+func GetValidators(client Client) (*[]stakingTypes.Validator, error) {
 	var nextKey []byte
 	var done = false
 
@@ -69,7 +99,7 @@ func GetValidators(client *http.HTTP) (*[]stakingTypes.Validator, error) {
 		data, _ := request.Marshal()
 
 		var ctx = context.Background()
-		bctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
 
 		// perform the ABCI query
 		raw, err := ABCIQuery(bctx, client, "/cosmos.staking.v1beta1.Query/Validators", data)
@@ -95,8 +125,10 @@ func GetValidators(client *http.HTTP) (*[]stakingTypes.Validator, error) {
 			done = true
 		}
 
-		// extract only the wanted data
+		// extract only the wanted data, unpacking the consensus pubkeys.
+		// The key types unknown to the exporter stay packed, matched by their raw bytes
 		for _, validator := range validatorsRes.Validators {
+			_ = validator.UnpackInterfaces(interfaceRegistry)
 			validators = append(validators, validator)
 		}
 	}
@@ -106,7 +138,7 @@ func GetValidators(client *http.HTTP) (*[]stakingTypes.Validator, error) {
 }
 
 // GetValidatorCommission queries the ABCI endpoint to get the Validator commissions
-func GetValidatorCommission(client *http.HTTP, validatorAddr string) (*types.DecCoins, error) {
+func GetValidatorCommission(client Client, validatorAddr string) (*types.DecCoins, error) {
 	// prepare the request data
 	var request = distributionTypes.QueryValidatorCommissionRequest{
 		ValidatorAddress: validatorAddr,
@@ -114,7 +146,7 @@ func GetValidatorCommission(client *http.HTTP, validatorAddr string) (*types.Dec
 	data, _ := request.Marshal()
 
 	var ctx = context.Background()
-	bctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
 
 	// perform the ABCI query
 	raw, err := ABCIQuery(bctx, client, "/cosmos.distribution.v1beta1.Query/ValidatorCommission", data)
@@ -139,7 +171,7 @@ func GetValidatorCommission(client *http.HTTP, validatorAddr string) (*types.Dec
 }
 
 // GetValidatorRewards queries the ABCI endpoint to get the Validator rewards
-func GetValidatorRewards(client *http.HTTP, validatorAddr string) (*types.DecCoins, error) {
+func GetValidatorRewards(client Client, validatorAddr string) (*types.DecCoins, error) {
 
 	// prepare the request data
 	var request = distributionTypes.QueryValidatorOutstandingRewardsRequest{
@@ -148,7 +180,7 @@ func GetValidatorRewards(client *http.HTTP, validatorAddr string) (*types.DecCoi
 	data, _ := request.Marshal()
 
 	var ctx = context.Background()
-	bctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
 
 	// perform the ABCI query
 	raw, err := ABCIQuery(bctx, client, "/cosmos.distribution.v1beta1.Query/ValidatorOutstandingRewards", data)
@@ -174,14 +206,14 @@ func GetValidatorRewards(client *http.HTTP, validatorAddr string) (*types.DecCoi
 
 // GetBech32Prefix queries the endpoint to get the Bech32 prefix used for addresses generation
 // Note: Available since Cosmos-Sdk v0.46
-func GetBech32Prefix(client *http.HTTP) (string, error) {
+func GetBech32Prefix(client Client) (string, error) {
 
 	// prepare the request data
 	var request = authTypes.Bech32PrefixRequest{}
 	data, _ := request.Marshal()
 
 	var ctx = context.Background()
-	bctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
 
 	// perform the ABCI query
 	raw, err := ABCIQuery(bctx, client, "/cosmos.auth.v1beta1.Query/Bech32Prefix", data)
@@ -206,7 +238,7 @@ func GetBech32Prefix(client *http.HTTP) (string, error) {
 }
 
 // GetBech32PrefixFromAuthAccounts queries the ABCI Bank accounts endpoint to get the first available Auth account
-func GetBech32PrefixFromAuthAccounts(client *http.HTTP) (string, error) {
+func GetBech32PrefixFromAuthAccounts(client Client) (string, error) {
 
 	// prepare the request data
 	var request = authTypes.QueryAccountsRequest{
@@ -218,7 +250,7 @@ func GetBech32PrefixFromAuthAccounts(client *http.HTTP) (string, error) {
 	data, _ := request.Marshal()
 
 	var ctx = context.Background()
-	bctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
 
 	// perform the ABCI query
 	raw, err := ABCIQuery(bctx, client, "/cosmos.auth.v1beta1.Query/Accounts", data)
@@ -250,3 +282,35 @@ func GetBech32PrefixFromAuthAccounts(client *http.HTTP) (string, error) {
 	return "", errors.New(fmt.Sprintf("Cannot get Bech32 Account from account type %s", response.Account.TypeUrl))
 
 }
+
+// GetStakingParams queries the ABCI endpoint to get the Staking module params
+func GetStakingParams(client Client) (*stakingTypes.Params, error) {
+
+	// prepare the request data
+	var request = stakingTypes.QueryParamsRequest{}
+	data, _ := request.Marshal()
+
+	var ctx = context.Background()
+	bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+
+	// perform the ABCI query
+	raw, err := ABCIQuery(bctx, client, "/cosmos.staking.v1beta1.Query/Params", data)
+	defer cancel()
+	if err != nil || raw.Response.Log != "" {
+		if raw.Response.Log != "" {
+			return nil, errors.New(fmt.Sprintf("Invalid Response Log: %s", raw.Response.Log))
+		}
+		return nil, err
+	}
+
+	// decode the response
+	var response stakingTypes.QueryParamsResponse
+	err = response.Unmarshal(raw.Response.GetValue())
+	if err != nil {
+		return nil, err
+	}
+
+	// return the wanted data
+	return &response.Params, nil
+
+}
@@ -0,0 +1,128 @@
+package abci
+
+import (
+	govV1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	stakingTypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"simple-exporter/testutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFixtures(t *testing.T) {
+	var votingEndTime = time.Date(2024, 10, 5, 16, 45, 12, 880914211, time.UTC)
+	for _, chain := range testutil.Chains {
+		t.Run(chain.Name, func(t *testing.T) {
+			var client = NewRPCClient(testutil.NewFixtureRPC(t, chain))
+			var legacy = chain.Name == "sdk-v0.45"
+
+			versionInfo, err := GetAppVersionInfo(client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if versionInfo.CosmosSdkVersion != chain.SDK {
+				t.Errorf("got Cosmos-Sdk version '%s', want '%s'", versionInfo.CosmosSdkVersion, chain.SDK)
+			}
+
+			validators, err := GetValidators(client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(*validators) < 3 {
+				t.Fatalf("got %d validators, want at least 3", len(*validators))
+			}
+			for _, validator := range *validators {
+				consPubKey, err := validator.ConsPubKey()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if validator.Status != stakingTypes.Bonded || validator.Description.Moniker == "" || !validator.Tokens.IsPositive() || consPubKey == nil {
+					t.Errorf("got validator %+v", validator)
+				}
+			}
+
+			stakingParams, err := GetStakingParams(client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stakingParams.MaxValidators != 180 || stakingParams.BondDenom != "uatom" {
+				t.Errorf("got staking params %+v", stakingParams)
+			}
+			slashingParams, err := GetSlashingParams(client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if slashingParams.SignedBlocksWindow != 10000 || slashingParams.DowntimeJailDuration != 10*time.Minute {
+				t.Errorf("got slashing params %+v", slashingParams)
+			}
+			signingInfo, err := GetValidatorSigningInfo(client, "cosmosvalcons1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if signingInfo.MissedBlocksCounter != 3 || !strings.HasPrefix(signingInfo.Address, "cosmosvalcons") {
+				t.Errorf("got signing info %+v", signingInfo)
+			}
+
+			// the gov v1 module is not available before v0.46, the v1beta1 one is the fallback
+			for _, legacyQuery := range []bool{false, legacy} {
+				proposals, err := GetVotingProposals(client, legacyQuery)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(proposals) != 1 || proposals[0].ID != 42 || !proposals[0].VotingEndTime.Equal(votingEndTime) {
+					t.Errorf("got proposals %+v", proposals)
+				}
+				options, err := GetGovVote(client, 42, "cosmos1", legacyQuery)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(options) != 1 || options[0] != govV1.OptionYes {
+					t.Errorf("got vote options %v", options)
+				}
+			}
+
+			prefix, err := GetBech32Prefix(client)
+			if legacy {
+				if err == nil {
+					t.Error("got the bech32 prefix from the auth module, not available before v0.46")
+				}
+				prefix, err = GetBech32PrefixFromAuthAccounts(client)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if prefix != "cosmos" {
+				t.Errorf("got bech32 prefix '%s'", prefix)
+			}
+
+			commission, err := GetValidatorCommission(client, (*validators)[0].OperatorAddress)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rewards, err := GetValidatorRewards(client, (*validators)[0].OperatorAddress)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if commission.AmountOf("uatom").IsZero() || rewards.AmountOf("uatom").IsZero() {
+				t.Errorf("got commission %s and rewards %s", commission, rewards)
+			}
+
+			balances, err := GetAllBalances(client, "cosmos1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			spendable, err := GetSpendableBalances(client, "cosmos1")
+			if legacy != (err != nil) || balances.AmountOf("uatom").IsZero() || (!legacy && !spendable.IsEqual(balances)) {
+				t.Errorf("got balances %s and spendable balances %s (%v)", balances, spendable, err)
+			}
+
+			plan, err := GetCurrentPlan(client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (chain.Name == "sdk-v0.47") != (plan != nil) {
+				t.Errorf("got upgrade plan %v", plan)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package abci
+
+import (
+	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/std"
+	authTypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	bankTypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distributionTypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	govV1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	govV1beta1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	slashingTypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	stakingTypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	upgradeTypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+)
+
+// interfaceRegistry holds the Any types known by the exporter (ex. the ed25519, secp256k1 and secp256r1 consensus pubkeys)
+var interfaceRegistry = newInterfaceRegistry()
+
+func newInterfaceRegistry() codecTypes.InterfaceRegistry {
+	var registry = codecTypes.NewInterfaceRegistry()
+	std.RegisterInterfaces(registry)
+	authTypes.RegisterInterfaces(registry)
+	bankTypes.RegisterInterfaces(registry)
+	distributionTypes.RegisterInterfaces(registry)
+	govV1.RegisterInterfaces(registry)
+	govV1beta1.RegisterInterfaces(registry)
+	slashingTypes.RegisterInterfaces(registry)
+	stakingTypes.RegisterInterfaces(registry)
+	upgradeTypes.RegisterInterfaces(registry)
+	return registry
+}
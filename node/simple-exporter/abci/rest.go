@@ -0,0 +1,342 @@
+package abci
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	authTypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	bankTypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distributionTypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	govV1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	govV1beta1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	slashingTypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	stakingTypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	upgradeTypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"google.golang.org/protobuf/encoding/protowire"
+	"io"
+	"net/http"
+	"net/url"
+	"simple-exporter/types"
+	"strconv"
+	"strings"
+)
+
+// RESTClient performs the ABCI queries through the node REST (LCD) endpoint, served by the gRPC gateway
+type RESTClient struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewRESTClient creates a RESTClient of the endpoint (ex. http://localhost:1317)
+func NewRESTClient(endpoint string) *RESTClient {
+	return &RESTClient{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   &http.Client{},
+	}
+}
+
+func (c *RESTClient) Query(ctx context.Context, path string, data []byte) (*types.ResultABCIQuery, error) {
+	route, ok := restRoutes[path]
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("query '%s' not supported by the REST endpoint", path))
+	}
+
+	// build the REST url from the request
+	var request = route.newRequest()
+	err := request.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var params = url.Values{}
+	var requestUrl = c.endpoint + route.url(request, params)
+	if paginated, ok := request.(interface{ GetPagination() *query.PageRequest }); ok {
+		setPaginationParams(paginated.GetPagination(), params)
+	}
+	if len(params) > 0 {
+		requestUrl += "?" + params.Encode()
+	}
+
+	// perform the REST request
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, requestUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	httpResponse, err := c.client.Do(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResponse.Body.Close()
+	body, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return nil, err
+	}
+	if httpResponse.StatusCode != http.StatusOK {
+		return restErrorResponse(httpResponse.StatusCode, body)
+	}
+
+	// decode the JSON response and encode it as the ABCI one
+	var response = route.newResponse()
+	err = restUnmarshaler.Unmarshal(strings.NewReader(string(body)), response)
+	if err != nil {
+		return nil, err
+	}
+	value, err := response.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return &types.ResultABCIQuery{
+		Response: types.ResponseQuery{
+			Value: value,
+		},
+	}, nil
+}
+
+// restErrorResponse reports the gRPC gateway errors as the ABCI ones, or as an endpoint error
+func restErrorResponse(statusCode int, body []byte) (*types.ResultABCIQuery, error) {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusNotImplemented, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return nil, errors.New(fmt.Sprintf("REST endpoint error: %d %s", statusCode, http.StatusText(statusCode)))
+	}
+
+	var gatewayError struct {
+		Code    uint32 `json:"code"`
+		Message string `json:"message"`
+	}
+	err := json.Unmarshal(body, &gatewayError)
+	if err != nil || gatewayError.Message == "" {
+		return nil, errors.New(fmt.Sprintf("REST endpoint error: %d %s", statusCode, http.StatusText(statusCode)))
+	}
+	return &types.ResultABCIQuery{
+		Response: types.ResponseQuery{
+			Code: gatewayError.Code,
+			Log:  gatewayError.Message,
+		},
+	}, nil
+}
+
+// setPaginationParams sets the gRPC gateway pagination query params
+func setPaginationParams(pagination *query.PageRequest, params url.Values) {
+	if pagination == nil {
+		return
+	}
+	if len(pagination.Key) > 0 {
+		params.Set("pagination.key", base64.StdEncoding.EncodeToString(pagination.Key))
+	}
+	if pagination.Offset > 0 {
+		params.Set("pagination.offset", strconv.FormatUint(pagination.Offset, 10))
+	}
+	if pagination.Limit > 0 {
+		params.Set("pagination.limit", strconv.FormatUint(pagination.Limit, 10))
+	}
+	if pagination.CountTotal {
+		params.Set("pagination.count_total", "true")
+	}
+	if pagination.Reverse {
+		params.Set("pagination.reverse", "true")
+	}
+}
+
+// restUnmarshaler decodes the JSON responses, ignoring the fields added by the newer chains
+var restUnmarshaler = &jsonpb.Unmarshaler{
+	AllowUnknownFields: true,
+	AnyResolver:        anyResolver{},
+}
+
+// anyResolver resolves the Any types of the JSON responses (ex. the validators consensus pubkey).
+// The types not registered (ex. the newer consensus pubkeys, or the messages of the newer gov proposals) keep their
+// type url and raw JSON fields
+type anyResolver struct{}
+
+func (anyResolver) Resolve(typeUrl string) (proto.Message, error) {
+	message, err := interfaceRegistry.Resolve(typeUrl)
+	if err != nil {
+		return &unknownAny{}, nil
+	}
+	return message, nil
+}
+
+// unknownAny holds the raw JSON fields of the not registered Any types. Their protobuf encoding is not known,
+// only the bytes key field shared by all the pubkey types is encoded, as the field 1 of the Any value
+type unknownAny struct {
+	fields map[string]json.RawMessage
+}
+
+func (a *unknownAny) Reset()         { a.fields = nil }
+func (a *unknownAny) String() string { return "unknown" }
+func (a *unknownAny) ProtoMessage()  {}
+
+func (a *unknownAny) UnmarshalJSONPB(_ *jsonpb.Unmarshaler, data []byte) error {
+	return json.Unmarshal(data, &a.fields)
+}
+
+func (a *unknownAny) Marshal() ([]byte, error) {
+	var key []byte
+	raw, ok := a.fields["key"]
+	if !ok || json.Unmarshal(raw, &key) != nil {
+		return nil, nil
+	}
+	return protowire.AppendBytes(protowire.AppendTag(nil, 1, protowire.BytesType), key), nil
+}
+
+// restRoute maps an ABCI query to its gRPC gateway url
+type restRoute struct {
+	newRequest  func() codec.ProtoMarshaler
+	newResponse func() codec.ProtoMarshaler
+	url         func(request codec.ProtoMarshaler, params url.Values) string // returns the url path, setting the query params
+}
+
+// restRoutes are the routes of the ABCI queries performed by the exporter
+var restRoutes = map[string]restRoute{
+	"/cosmos.slashing.v1beta1.Query/SigningInfo": {
+		func() codec.ProtoMarshaler { return &slashingTypes.QuerySigningInfoRequest{} },
+		func() codec.ProtoMarshaler { return &slashingTypes.QuerySigningInfoResponse{} },
+		func(request codec.ProtoMarshaler, params url.Values) string {
+			return "/cosmos/slashing/v1beta1/signing_infos/" + url.PathEscape(request.(*slashingTypes.QuerySigningInfoRequest).ConsAddress)
+		},
+	},
+	"/cosmos.slashing.v1beta1.Query/Params": {
+		func() codec.ProtoMarshaler { return &slashingTypes.QueryParamsRequest{} },
+		func() codec.ProtoMarshaler { return &slashingTypes.QueryParamsResponse{} },
+		func(request codec.ProtoMarshaler, params url.Values) string {
+			return "/cosmos/slashing/v1beta1/params"
+		},
+	},
+	"/cosmos.staking.v1beta1.Query/Validators": {
+		func() codec.ProtoMarshaler { return &stakingTypes.QueryValidatorsRequest{} },
+		func() codec.ProtoMarshaler { return &stakingTypes.QueryValidatorsResponse{} },
+		func(request codec.ProtoMarshaler, params url.Values) string {
+			return "/cosmos/staking/v1beta1/validators"
+		},
+	},
+	"/cosmos.staking.v1beta1.Query/Params": {
+		func() codec.ProtoMarshaler { return &stakingTypes.QueryParamsRequest{} },
+		func() codec.ProtoMarshaler { return &stakingTypes.QueryParamsResponse{} },
+		func(request codec.ProtoMarshaler, params url.Values) string {
+			return "/cosmos/staking/v1beta1/params"
+		},
+	},
+	"/cosmos.staking.v1beta1.Query/ValidatorDelegations": {
+		func() codec.ProtoMarshaler { return &stakingTypes.QueryValidatorDelegationsRequest{} },
+		func() codec.ProtoMarshaler { return &stakingTypes.QueryValidatorDelegationsResponse{} },
+		func(request codec.ProtoMarshaler, params url.Values) string {
+			return "/cosmos/staking/v1beta1/validators/" + url.PathEscape(request.(*stakingTypes.QueryValidatorDelegationsRequest).ValidatorAddr) + "/delegations"
+		},
+	},
+	"/cosmos.staking.v1beta1.Query/ValidatorUnbondingDelegations": {
+		func() codec.ProtoMarshaler { return &stakingTypes.QueryValidatorUnbondingDelegationsRequest{} },
+		func() codec.ProtoMarshaler { return &stakingTypes.QueryValidatorUnbondingDelegationsResponse{} },
+		func(request codec.ProtoMarshaler, params url.Values) string {
+			return "/cosmos/staking/v1beta1/validators/" + url.PathEscape(request.(*stakingTypes.QueryValidatorUnbondingDelegationsRequest).ValidatorAddr) + "/unbonding_delegations"
+		},
+	},
+	"/cosmos.staking.v1beta1.Query/Delegation": {
+		func() codec.ProtoMarshaler { return &stakingTypes.QueryDelegationRequest{} },
+		func() codec.ProtoMarshaler { return &stakingTypes.QueryDelegationResponse{} },
+		func(request codec.ProtoMarshaler, params url.Values) string {
+			var delegationRequest = request.(*stakingTypes.QueryDelegationRequest)
+			return "/cosmos/staking/v1beta1/validators/" + url.PathEscape(delegationRequest.ValidatorAddr) + "/delegations/" + url.PathEscape(delegationRequest.DelegatorAddr)
+		},
+	},
+	"/cosmos.distribution.v1beta1.Query/ValidatorCommission": {
+		func() codec.ProtoMarshaler { return &distributionTypes.QueryValidatorCommissionRequest{} },
+		func() codec.ProtoMarshaler { return &distributionTypes.QueryValidatorCommissionResponse{} },
+		func(request codec.ProtoMarshaler, params url.Values) string {
+			return "/cosmos/distribution/v1beta1/validators/" + url.PathEscape(request.(*distributionTypes.QueryValidatorCommissionRequest).ValidatorAddress) + "/commission"
+		},
+	},
+	"/cosmos.distribution.v1beta1.Query/ValidatorOutstandingRewards": {
+		func() codec.ProtoMarshaler { return &distributionTypes.QueryValidatorOutstandingRewardsRequest{} },
+		func() codec.ProtoMarshaler { return &distributionTypes.QueryValidatorOutstandingRewardsResponse{} },
+		func(request codec.ProtoMarshaler, params url.Values) string {
+			return "/cosmos/distribution/v1beta1/validators/" + url.PathEscape(request.(*distributionTypes.QueryValidatorOutstandingRewardsRequest).ValidatorAddress) + "/outstanding_rewards"
+		},
+	},
+	"/cosmos.auth.v1beta1.Query/Bech32Prefix": {
+		func() codec.ProtoMarshaler { return &authTypes.Bech32PrefixRequest{} },
+		func() codec.ProtoMarshaler { return &authTypes.Bech32PrefixResponse{} },
+		func(request codec.ProtoMarshaler, params url.Values) string {
+			return "/cosmos/auth/v1beta1/bech32"
+		},
+	},
+	"/cosmos.auth.v1beta1.Query/Accounts": {
+		func() codec.ProtoMarshaler { return &authTypes.QueryAccountsRequest{} },
+		func() codec.ProtoMarshaler { return &authTypes.QueryAccountsResponse{} },
+		func(request codec.ProtoMarshaler, params url.Values) string {
+			return "/cosmos/auth/v1beta1/accounts"
+		},
+	},
+	"/cosmos.gov.v1.Query/Proposals": {
+		func() codec.ProtoMarshaler { return &govV1.QueryProposalsRequest{} },
+		func() codec.ProtoMarshaler { return &govV1.QueryProposalsResponse{} },
+		func(request codec.ProtoMarshaler, params url.Values) string {
+			params.Set("proposal_status", request.(*govV1.QueryProposalsRequest).ProposalStatus.String())
+			return "/cosmos/gov/v1/proposals"
+		},
+	},
+	"/cosmos.gov.v1beta1.Query/Proposals": {
+		func() codec.ProtoMarshaler { return &govV1beta1.QueryProposalsRequest{} },
+		func() codec.ProtoMarshaler { return &govV1beta1.QueryProposalsResponse{} },
+		func(request codec.ProtoMarshaler, params url.Values) string {
+			params.Set("proposal_status", request.(*govV1beta1.QueryProposalsRequest).ProposalStatus.String())
+			return "/cosmos/gov/v1beta1/proposals"
+		},
+	},
+	"/cosmos.gov.v1.Query/Vote": {
+		func() codec.ProtoMarshaler { return &govV1.QueryVoteRequest{} },
+		func() codec.ProtoMarshaler { return &govV1.QueryVoteResponse{} },
+		func(request codec.ProtoMarshaler, params url.Values) string {
+			var voteRequest = request.(*govV1.QueryVoteRequest)
+			return "/cosmos/gov/v1/proposals/" + strconv.FormatUint(voteRequest.ProposalId, 10) + "/votes/" + url.PathEscape(voteRequest.Voter)
+		},
+	},
+	"/cosmos.gov.v1beta1.Query/Vote": {
+		func() codec.ProtoMarshaler { return &govV1beta1.QueryVoteRequest{} },
+		func() codec.ProtoMarshaler { return &govV1beta1.QueryVoteResponse{} },
+		func(request codec.ProtoMarshaler, params url.Values) string {
+			var voteRequest = request.(*govV1beta1.QueryVoteRequest)
+			return "/cosmos/gov/v1beta1/proposals/" + strconv.FormatUint(voteRequest.ProposalId, 10) + "/votes/" + url.PathEscape(voteRequest.Voter)
+		},
+	},
+	"/cosmos.upgrade.v1beta1.Query/CurrentPlan": {
+		func() codec.ProtoMarshaler { return &upgradeTypes.QueryCurrentPlanRequest{} },
+		func() codec.ProtoMarshaler { return &upgradeTypes.QueryCurrentPlanResponse{} },
+		func(request codec.ProtoMarshaler, params url.Values) string {
+			return "/cosmos/upgrade/v1beta1/current_plan"
+		},
+	},
+	"/cosmos.upgrade.v1beta1.Query/AppliedPlan": {
+		func() codec.ProtoMarshaler { return &upgradeTypes.QueryAppliedPlanRequest{} },
+		func() codec.ProtoMarshaler { return &upgradeTypes.QueryAppliedPlanResponse{} },
+		func(request codec.ProtoMarshaler, params url.Values) string {
+			return "/cosmos/upgrade/v1beta1/applied_plan/" + url.PathEscape(request.(*upgradeTypes.QueryAppliedPlanRequest).Name)
+		},
+	},
+	"/cosmos.base.tendermint.v1beta1.Service/GetNodeInfo": {
+		func() codec.ProtoMarshaler { return &tmservice.GetNodeInfoRequest{} },
+		func() codec.ProtoMarshaler { return &tmservice.GetNodeInfoResponse{} },
+		func(request codec.ProtoMarshaler, params url.Values) string {
+			return "/cosmos/base/tendermint/v1beta1/node_info"
+		},
+	},
+	"/cosmos.bank.v1beta1.Query/AllBalances": {
+		func() codec.ProtoMarshaler { return &bankTypes.QueryAllBalancesRequest{} },
+		func() codec.ProtoMarshaler { return &bankTypes.QueryAllBalancesResponse{} },
+		func(request codec.ProtoMarshaler, params url.Values) string {
+			return "/cosmos/bank/v1beta1/balances/" + url.PathEscape(request.(*bankTypes.QueryAllBalancesRequest).Address)
+		},
+	},
+	"/cosmos.bank.v1beta1.Query/SpendableBalances": {
+		func() codec.ProtoMarshaler { return &bankTypes.QuerySpendableBalancesRequest{} },
+		func() codec.ProtoMarshaler { return &bankTypes.QuerySpendableBalancesResponse{} },
+		func(request codec.ProtoMarshaler, params url.Values) string {
+			return "/cosmos/bank/v1beta1/spendable_balances/" + url.PathEscape(request.(*bankTypes.QuerySpendableBalancesRequest).Address)
+		},
+	},
+}
@@ -0,0 +1,235 @@
+package abci
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	govV1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	slashingTypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	stakingTypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"google.golang.org/protobuf/encoding/protowire"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestRESTServer serves the given JSON body with the status code like a node REST endpoint, recording the request urls
+func newTestRESTServer(t *testing.T, statusCode int, body string, urls *[]string) string {
+	t.Helper()
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*urls = append(*urls, r.URL.RequestURI())
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestRESTClientRoutes(t *testing.T) {
+	// the key is sent as the standard base64 encoding, holding the characters escaped in the urls
+	var pageKey = []byte{0xfb, 0xff, 0xfe, 0x01}
+
+	var tests = []struct {
+		name    string
+		path    string
+		request interface{ Marshal() ([]byte, error) }
+		want    string
+	}{
+		{
+			name:    "path parameter",
+			path:    "/cosmos.slashing.v1beta1.Query/SigningInfo",
+			request: &slashingTypes.QuerySigningInfoRequest{ConsAddress: "cosmosvalcons1abc"},
+			want:    "/cosmos/slashing/v1beta1/signing_infos/cosmosvalcons1abc",
+		},
+		{
+			name:    "two path parameters",
+			path:    "/cosmos.staking.v1beta1.Query/Delegation",
+			request: &stakingTypes.QueryDelegationRequest{DelegatorAddr: "cosmos1abc", ValidatorAddr: "cosmosvaloper1abc"},
+			want:    "/cosmos/staking/v1beta1/validators/cosmosvaloper1abc/delegations/cosmos1abc",
+		},
+		{
+			name:    "first page",
+			path:    "/cosmos.staking.v1beta1.Query/Validators",
+			request: &stakingTypes.QueryValidatorsRequest{Pagination: &query.PageRequest{Limit: 100, CountTotal: true}},
+			want:    "/cosmos/staking/v1beta1/validators?pagination.count_total=true&pagination.limit=100",
+		},
+		{
+			name:    "next page key",
+			path:    "/cosmos.staking.v1beta1.Query/ValidatorDelegations",
+			request: &stakingTypes.QueryValidatorDelegationsRequest{ValidatorAddr: "cosmosvaloper1abc", Pagination: &query.PageRequest{Key: pageKey, Limit: 100}},
+			want:    "/cosmos/staking/v1beta1/validators/cosmosvaloper1abc/delegations?pagination.key=%2B%2F%2F%2BAQ%3D%3D&pagination.limit=100",
+		},
+		{
+			name:    "query parameter",
+			path:    "/cosmos.gov.v1.Query/Proposals",
+			request: &govV1.QueryProposalsRequest{ProposalStatus: govV1.StatusVotingPeriod},
+			want:    "/cosmos/gov/v1/proposals?proposal_status=PROPOSAL_STATUS_VOTING_PERIOD",
+		},
+		{
+			name:    "numeric path parameter",
+			path:    "/cosmos.gov.v1.Query/Vote",
+			request: &govV1.QueryVoteRequest{ProposalId: 42, Voter: "cosmos1abc"},
+			want:    "/cosmos/gov/v1/proposals/42/votes/cosmos1abc",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var urls []string
+			// the endpoint trailing slash is not repeated in the urls
+			var client = NewRESTClient(newTestRESTServer(t, http.StatusOK, "{}", &urls) + "/")
+			_, err := client.Query(context.Background(), test.path, mustMarshal(t, test.request))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(urls) != 1 || urls[0] != test.want {
+				t.Errorf("got urls %v, want %s", urls, test.want)
+			}
+		})
+	}
+
+	// the queries without a route are not sent
+	var urls []string
+	_, err := NewRESTClient(newTestRESTServer(t, http.StatusOK, "{}", &urls)).Query(context.Background(), "/cosmos.mint.v1beta1.Query/Params", nil)
+	if err == nil || len(urls) != 0 {
+		t.Errorf("got error %v and urls %v for a query without route", err, urls)
+	}
+}
+
+func TestRESTErrorResponse(t *testing.T) {
+	var tests = []struct {
+		name       string
+		statusCode int
+		body       string
+		wantCode   uint32
+		wantLog    string
+		wantErr    string
+	}{
+		{
+			name:       "not found application error",
+			statusCode: http.StatusNotFound,
+			body:       `{"code": 5, "message": "validator does not exist", "details": []}`,
+			wantCode:   5,
+			wantLog:    "validator does not exist",
+		},
+		{
+			name:       "invalid request application error",
+			statusCode: http.StatusBadRequest,
+			body:       `{"code": 3, "message": "invalid address"}`,
+			wantCode:   3,
+			wantLog:    "invalid address",
+		},
+		{
+			name:       "route not served by an older chain",
+			statusCode: http.StatusNotImplemented,
+			body:       `{"code": 12, "message": "Not Implemented"}`,
+			wantErr:    "REST endpoint error: 501 Not Implemented",
+		},
+		{
+			name:       "rate limited",
+			statusCode: http.StatusTooManyRequests,
+			body:       `{"code": 8, "message": "too many requests"}`,
+			wantErr:    "REST endpoint error: 429 Too Many Requests",
+		},
+		{
+			name:       "proxy error page",
+			statusCode: http.StatusBadGateway,
+			body:       "<html>502 Bad Gateway</html>",
+			wantErr:    "REST endpoint error: 502 Bad Gateway",
+		},
+		{
+			name:       "not a gateway error",
+			statusCode: http.StatusNotFound,
+			body:       "404 page not found",
+			wantErr:    "REST endpoint error: 404 Not Found",
+		},
+		{
+			name:       "internal error without message",
+			statusCode: http.StatusInternalServerError,
+			body:       `{"code": 2}`,
+			wantErr:    "REST endpoint error: 500 Internal Server Error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var urls []string
+			var client = NewRESTClient(newTestRESTServer(t, test.statusCode, test.body, &urls))
+			response, err := client.Query(context.Background(), "/cosmos.slashing.v1beta1.Query/Params", nil)
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Errorf("got response %+v and error %v, want '%s'", response, err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if response.Response.Code != test.wantCode || response.Response.Log != test.wantLog {
+				t.Errorf("got code %d and log '%s', want %d and '%s'", response.Response.Code, response.Response.Log, test.wantCode, test.wantLog)
+			}
+		})
+	}
+}
+
+func TestRESTClientValidators(t *testing.T) {
+	var ed25519Key = bytes.Repeat([]byte{1}, 32)
+	var blsKey = bytes.Repeat([]byte{2}, 48)
+	var body = `{
+		"validators": [
+			{
+				"operator_address": "cosmosvaloper1ed25519",
+				"consensus_pubkey": {"@type": "/cosmos.crypto.ed25519.PubKey", "key": "` + base64.StdEncoding.EncodeToString(ed25519Key) + `"},
+				"jailed": false,
+				"status": "BOND_STATUS_BONDED",
+				"tokens": "1000",
+				"delegator_shares": "1000.000000000000000000",
+				"description": {"moniker": "ed25519"},
+				"unbonding_on_hold_ref_count": "0"
+			},
+			{
+				"operator_address": "cosmosvaloper1bls",
+				"consensus_pubkey": {"@type": "/cosmos.crypto.bls12_381.PubKey", "key": "` + base64.StdEncoding.EncodeToString(blsKey) + `"},
+				"jailed": true,
+				"status": "BOND_STATUS_UNBONDING",
+				"tokens": "500",
+				"delegator_shares": "500.000000000000000000",
+				"description": {"moniker": "bls"}
+			}
+		],
+		"pagination": {"next_key": null, "total": "2"}
+	}`
+	var urls []string
+	var client = NewRESTClient(newTestRESTServer(t, http.StatusOK, body, &urls))
+	response, err := client.Query(context.Background(), "/cosmos.staking.v1beta1.Query/Validators", mustMarshal(t, &stakingTypes.QueryValidatorsRequest{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var validators stakingTypes.QueryValidatorsResponse
+	err = validators.Unmarshal(response.Response.Value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(validators.Validators) != 2 || validators.Pagination.Total != 2 {
+		t.Fatalf("got %d validators of %d", len(validators.Validators), validators.Pagination.GetTotal())
+	}
+
+	// the registered pubkeys are encoded as their type
+	var ed25519Validator = validators.Validators[0]
+	var ed25519PubKey ed25519.PubKey
+	err = ed25519PubKey.Unmarshal(ed25519Validator.ConsensusPubkey.Value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ed25519Validator.ConsensusPubkey.TypeUrl != "/cosmos.crypto.ed25519.PubKey" || !bytes.Equal(ed25519PubKey.Key, ed25519Key) || ed25519Validator.Tokens.Int64() != 1000 {
+		t.Errorf("got validator %s with pubkey %s %X", ed25519Validator.OperatorAddress, ed25519Validator.ConsensusPubkey.TypeUrl, ed25519PubKey.Key)
+	}
+
+	// the not registered pubkeys keep their type url and key bytes
+	var blsValidator = validators.Validators[1]
+	var wantValue = protowire.AppendBytes(protowire.AppendTag(nil, 1, protowire.BytesType), blsKey)
+	if blsValidator.ConsensusPubkey.TypeUrl != "/cosmos.crypto.bls12_381.PubKey" || !bytes.Equal(blsValidator.ConsensusPubkey.Value, wantValue) || !blsValidator.Jailed {
+		t.Errorf("got validator %s with pubkey %s %X", blsValidator.OperatorAddress, blsValidator.ConsensusPubkey.TypeUrl, blsValidator.ConsensusPubkey.Value)
+	}
+}
@@ -0,0 +1,35 @@
+package abci
+
+import (
+	"context"
+	tmhttp "github.com/tendermint/tendermint/rpc/client/http"
+	"simple-exporter/types"
+)
+
+// RPCClient performs the ABCI queries through the node RPC endpoint /abci_query
+type RPCClient struct {
+	client *tmhttp.HTTP
+}
+
+func NewRPCClient(client *tmhttp.HTTP) *RPCClient {
+	return &RPCClient{client: client}
+}
+
+func (c *RPCClient) Query(ctx context.Context, path string, data []byte) (*types.ResultABCIQuery, error) {
+	response, err := c.client.ABCIQuery(ctx, path, data)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ResultABCIQuery{
+		Response: types.ResponseQuery{
+			Code:      response.Response.Code,
+			Log:       response.Response.Log,
+			Info:      response.Response.Info,
+			Index:     response.Response.Index,
+			Key:       response.Response.Key,
+			Value:     response.Response.Value,
+			Height:    response.Response.Height,
+			Codespace: response.Response.Codespace,
+		},
+	}, nil
+}
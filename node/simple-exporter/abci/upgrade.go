@@ -0,0 +1,74 @@
+package abci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	upgradeTypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+)
+
+// GetCurrentPlan queries the ABCI endpoint to get the scheduled upgrade plan, nil if no upgrade is scheduled
+func GetCurrentPlan(client Client) (*upgradeTypes.Plan, error) {
+
+	// prepare the request data
+	var request = upgradeTypes.QueryCurrentPlanRequest{}
+	data, _ := request.Marshal()
+
+	var ctx = context.Background()
+	bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+
+	// perform the ABCI query
+	raw, err := ABCIQuery(bctx, client, "/cosmos.upgrade.v1beta1.Query/CurrentPlan", data)
+	defer cancel()
+	if err != nil || raw.Response.Log != "" {
+		if raw.Response.Log != "" {
+			return nil, errors.New(fmt.Sprintf("Invalid Response Log: %s", raw.Response.Log))
+		}
+		return nil, err
+	}
+
+	// decode the response
+	var response upgradeTypes.QueryCurrentPlanResponse
+	err = response.Unmarshal(raw.Response.GetValue())
+	if err != nil {
+		return nil, err
+	}
+
+	// return the wanted data
+	return response.Plan, nil
+
+}
+
+// GetAppliedPlan queries the ABCI endpoint to get the height at which the upgrade plan was applied, 0 if not applied
+func GetAppliedPlan(client Client, name string) (int64, error) {
+
+	// prepare the request data
+	var request = upgradeTypes.QueryAppliedPlanRequest{
+		Name: name,
+	}
+	data, _ := request.Marshal()
+
+	var ctx = context.Background()
+	bctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+
+	// perform the ABCI query
+	raw, err := ABCIQuery(bctx, client, "/cosmos.upgrade.v1beta1.Query/AppliedPlan", data)
+	defer cancel()
+	if err != nil || raw.Response.Log != "" {
+		if raw.Response.Log != "" {
+			return 0, errors.New(fmt.Sprintf("Invalid Response Log: %s", raw.Response.Log))
+		}
+		return 0, err
+	}
+
+	// decode the response
+	var response upgradeTypes.QueryAppliedPlanResponse
+	err = response.Unmarshal(raw.Response.GetValue())
+	if err != nil {
+		return 0, err
+	}
+
+	// return the wanted data
+	return response.Height, nil
+
+}
@@ -0,0 +1,295 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Config holds the exporter configuration
+type Config struct {
+	ListenPort          uint     `yaml:"listen_port" toml:"listen_port"`                     // port of the Prometheus metrics endpoint
+	PollInterval        Duration `yaml:"poll_interval" toml:"poll_interval"`                 // metrics refresh interval while the websocket is not available
+	RetryDelay          Duration `yaml:"retry_delay" toml:"retry_delay"`                     // delay before retrying after a failed update
+	QueryTimeout        Duration `yaml:"query_timeout" toml:"query_timeout"`                 // timeout of the RPC and ABCI queries
+	WsIdleTimeout       Duration `yaml:"ws_idle_timeout" toml:"ws_idle_timeout"`             // max time without new blocks before considering the websocket dropped
+	WsResubscribeDelay  Duration `yaml:"ws_resubscribe_delay" toml:"ws_resubscribe_delay"`   // time spent polling before subscribing again to the websocket
+	UptimeWindow        int      `yaml:"uptime_window" toml:"uptime_window"`                 // blocks window used for the validators uptime
+	DelegationsRefresh  Duration `yaml:"delegations_refresh" toml:"delegations_refresh"`     // min interval between the validators delegations updates, heavy on big validators
+	ConsumerRefresh     Duration `yaml:"consumer_refresh" toml:"consumer_refresh"`           // min interval between the ICS provider validators and consumer keys updates, heavy on the provider
+	BlockStaleThreshold Duration `yaml:"block_stale_threshold" toml:"block_stale_threshold"` // max time since the latest block before considering the node blocks stale
+	Targets             []Target `yaml:"targets" toml:"targets"`
+}
+
+// Target holds the configuration of a monitored node
+type Target struct {
+	Name                     string    `yaml:"name" toml:"name"`                                             // unique name, used as the metrics "target" label
+	ChainID                  string    `yaml:"chain_id" toml:"chain_id"`                                     // optional, fetched from the node if empty
+	RPC                      string    `yaml:"rpc" toml:"rpc"`                                               // node RPC endpoint (ex. http://localhost:26657)
+	References               []string  `yaml:"references" toml:"references"`                                 // optional reference RPC endpoints of the same chain (ex. other sentries), measuring the node lag, detecting the blocks divergences and telling a stuck node from a halted chain
+	Peers                    []string  `yaml:"peers" toml:"peers"`                                           // optional node ids (or id@host:port addresses) of the persistent peers or sentries expected to be connected to the node
+	GRPC                     string    `yaml:"grpc" toml:"grpc"`                                             // optional node gRPC endpoint (ex. localhost:9090, https:// for TLS), used for the chain queries with the RPC
+	REST                     string    `yaml:"rest" toml:"rest"`                                             // optional node REST (LCD) endpoint (ex. http://localhost:1317), used for the chain queries with the RPC
+	Transport                string    `yaml:"transport" toml:"transport"`                                   // transport of the chain queries, "rpc" (default), "grpc" or "rest", falling back to the other ones
+	Bech32Prefix             string    `yaml:"bech32_prefix" toml:"bech32_prefix"`                           // optional, queried from the chain if empty
+	Validators               []string  `yaml:"validators" toml:"validators"`                                 // optional valoper/valcons addresses or monikers, the node validator if empty (required by the consumer chains)
+	ProviderRPC              string    `yaml:"provider_rpc" toml:"provider_rpc"`                             // optional ICS provider node RPC endpoint, monitoring the target as a consumer chain of the provider validators
+	ConsumerID               string    `yaml:"consumer_id" toml:"consumer_id"`                               // optional consumer id on the ICS provider (since ICS v6), the chain id if empty
+	DaemonHome               string    `yaml:"daemon_home" toml:"daemon_home"`                               // optional node home, mounted to check the cosmovisor upgrades readiness
+	DaemonName               string    `yaml:"daemon_name" toml:"daemon_name"`                               // optional node binary name in the cosmovisor folders, any executable if empty
+	Accounts                 []Account `yaml:"accounts" toml:"accounts"`                                     // optional extra accounts to monitor the balances of (ex. oracle feeders, relayers)
+	LargeDelegationThreshold float64   `yaml:"large_delegation_threshold" toml:"large_delegation_threshold"` // optional delegation change (base denom tokens) counted as large, disabled if 0
+}
+
+// Account holds the configuration of a monitored account balance
+type Account struct {
+	Address   string   `yaml:"address" toml:"address"`
+	Alias     string   `yaml:"alias" toml:"alias"`         // optional, used as the metrics "alias" label
+	Spendable bool     `yaml:"spendable" toml:"spendable"` // also export the spendable balances, without the vesting ones (since Cosmos-Sdk v0.46)
+	Denoms    []string `yaml:"denoms" toml:"denoms"`       // optional denoms always exported, as 0 while the account doesn't hold them
+}
+
+// chain queries transports
+const (
+	TransportRPC  = "rpc"
+	TransportGRPC = "grpc"
+	TransportREST = "rest"
+)
+
+// Duration is a time.Duration decoded from a string (ex. "10s")
+type Duration time.Duration
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	value, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(value)
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// Default returns the default configuration, without targets
+func Default() *Config {
+	return &Config{
+		ListenPort:          9090,
+		PollInterval:        Duration(3 * time.Second),
+		RetryDelay:          Duration(10 * time.Second),
+		QueryTimeout:        Duration(10 * time.Second),
+		WsIdleTimeout:       Duration(60 * time.Second),
+		WsResubscribeDelay:  Duration(30 * time.Second),
+		UptimeWindow:        100,
+		DelegationsRefresh:  Duration(5 * time.Minute),
+		ConsumerRefresh:     Duration(5 * time.Minute),
+		BlockStaleThreshold: Duration(60 * time.Second),
+	}
+}
+
+// Load loads the configuration from the defaults, the file (if any), the env and the set flags, in order of priority
+func Load(path string, flags *flag.FlagSet) (*Config, error) {
+	var config = Default()
+	if path != "" {
+		err := config.loadFile(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var overrides = &overrides{}
+	err := config.applyEnv(overrides)
+	if err != nil {
+		return nil, err
+	}
+	err = config.applyFlags(overrides, flags)
+	if err != nil {
+		return nil, err
+	}
+	err = overrides.apply(config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// loadFile decodes the YAML or TOML configuration file, rejecting unknown fields
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		var decoder = toml.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(c)
+	case ".yaml", ".yml":
+		var decoder = yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(c)
+	default:
+		return errors.New(fmt.Sprintf("unsupported config file format '%s' (use .yaml, .yml or .toml)", filepath.Ext(path)))
+	}
+	if err != nil {
+		// report the unknown toml fields details
+		var strictErr *toml.StrictMissingError
+		if errors.As(err, &strictErr) {
+			return errors.New(fmt.Sprintf("cannot decode '%s': %s", path, strictErr.String()))
+		}
+		return errors.New(fmt.Sprintf("cannot decode '%s': %s", path, err.Error()))
+	}
+	return nil
+}
+
+var bech32PrefixRegex = regexp.MustCompile("^[a-z][a-z0-9]*$")
+
+var peerRegex = regexp.MustCompile("^[0-9a-fA-F]{40}(@.+)?$")
+
+// Validate checks the configuration, reporting all the found mistakes
+func (c *Config) Validate() error {
+	var errs []error
+	if c.ListenPort == 0 || c.ListenPort > 65535 {
+		errs = append(errs, errors.New(fmt.Sprintf("listen_port: %d is not a valid port", c.ListenPort)))
+	}
+	var durations = []struct {
+		name  string
+		value Duration
+	}{
+		{"poll_interval", c.PollInterval},
+		{"retry_delay", c.RetryDelay},
+		{"query_timeout", c.QueryTimeout},
+		{"ws_idle_timeout", c.WsIdleTimeout},
+		{"ws_resubscribe_delay", c.WsResubscribeDelay},
+		{"delegations_refresh", c.DelegationsRefresh},
+		{"consumer_refresh", c.ConsumerRefresh},
+		{"block_stale_threshold", c.BlockStaleThreshold},
+	}
+	for _, duration := range durations {
+		if duration.value <= 0 {
+			errs = append(errs, errors.New(fmt.Sprintf("%s: must be a positive duration", duration.name)))
+		}
+	}
+	if c.UptimeWindow < 1 {
+		errs = append(errs, errors.New(fmt.Sprintf("uptime_window: must be at least 1 block, got %d", c.UptimeWindow)))
+	}
+
+	// without targets the exporter only serves the /probe requests
+	var names = make(map[string]bool)
+	for i, target := range c.Targets {
+		var field = fmt.Sprintf("targets[%d]", i)
+		if target.Name == "" {
+			errs = append(errs, errors.New(fmt.Sprintf("%s.name: is required", field)))
+		} else if names[target.Name] {
+			errs = append(errs, errors.New(fmt.Sprintf("%s.name: duplicated target name '%s'", field, target.Name)))
+		}
+		names[target.Name] = true
+
+		if target.RPC == "" {
+			errs = append(errs, errors.New(fmt.Sprintf("%s.rpc: is required", field)))
+		} else if !isValidEndpoint(target.RPC) {
+			errs = append(errs, errors.New(fmt.Sprintf("%s.rpc: '%s' is not a valid http(s) or tcp endpoint", field, target.RPC)))
+		}
+		for j, reference := range target.References {
+			if !isValidEndpoint(reference) {
+				errs = append(errs, errors.New(fmt.Sprintf("%s.references[%d]: '%s' is not a valid http(s) or tcp endpoint", field, j, reference)))
+			}
+		}
+		for j, peer := range target.Peers {
+			if !peerRegex.MatchString(peer) {
+				errs = append(errs, errors.New(fmt.Sprintf("%s.peers[%d]: '%s' is not a valid node id or id@host:port address", field, j, peer)))
+			}
+		}
+		switch target.Transport {
+		case "", TransportRPC:
+		case TransportGRPC:
+			if target.GRPC == "" {
+				errs = append(errs, errors.New(fmt.Sprintf("%s.grpc: is required by the grpc transport", field)))
+			}
+		case TransportREST:
+			if target.REST == "" {
+				errs = append(errs, errors.New(fmt.Sprintf("%s.rest: is required by the rest transport", field)))
+			}
+		default:
+			errs = append(errs, errors.New(fmt.Sprintf("%s.transport: '%s' is not a valid transport (use rpc, grpc or rest)", field, target.Transport)))
+		}
+		if target.GRPC != "" && !isValidGRPCEndpoint(target.GRPC) {
+			errs = append(errs, errors.New(fmt.Sprintf("%s.grpc: '%s' is not a valid host:port endpoint", field, target.GRPC)))
+		}
+		if target.REST != "" && (!isValidEndpoint(target.REST) || strings.HasPrefix(target.REST, "tcp://")) {
+			errs = append(errs, errors.New(fmt.Sprintf("%s.rest: '%s' is not a valid http(s) endpoint", field, target.REST)))
+		}
+		if target.ProviderRPC != "" && !isValidEndpoint(target.ProviderRPC) {
+			errs = append(errs, errors.New(fmt.Sprintf("%s.provider_rpc: '%s' is not a valid http(s) or tcp endpoint", field, target.ProviderRPC)))
+		}
+		if target.ProviderRPC != "" && len(target.Validators) == 0 {
+			errs = append(errs, errors.New(fmt.Sprintf("%s.validators: the provider validators are required by the consumer chains", field)))
+		}
+		if target.ConsumerID != "" && target.ProviderRPC == "" {
+			errs = append(errs, errors.New(fmt.Sprintf("%s.consumer_id: requires the provider_rpc", field)))
+		}
+		if target.Bech32Prefix != "" && !bech32PrefixRegex.MatchString(target.Bech32Prefix) {
+			errs = append(errs, errors.New(fmt.Sprintf("%s.bech32_prefix: '%s' is not a valid prefix", field, target.Bech32Prefix)))
+		}
+		if target.DaemonHome != "" {
+			info, err := os.Stat(target.DaemonHome)
+			if err != nil || !info.IsDir() {
+				errs = append(errs, errors.New(fmt.Sprintf("%s.daemon_home: '%s' is not an existing directory", field, target.DaemonHome)))
+			}
+		}
+		if target.LargeDelegationThreshold < 0 {
+			errs = append(errs, errors.New(fmt.Sprintf("%s.large_delegation_threshold: must not be negative", field)))
+		}
+		for j, account := range target.Accounts {
+			if account.Address == "" {
+				errs = append(errs, errors.New(fmt.Sprintf("%s.accounts[%d].address: is required", field, j)))
+			} else if _, _, err := bech32.DecodeAndConvert(account.Address); err != nil {
+				errs = append(errs, errors.New(fmt.Sprintf("%s.accounts[%d].address: '%s' is not a valid bech32 address", field, j, account.Address)))
+			}
+			for k, denom := range account.Denoms {
+				if strings.TrimSpace(denom) == "" {
+					errs = append(errs, errors.New(fmt.Sprintf("%s.accounts[%d].denoms[%d]: is empty", field, j, k)))
+				}
+			}
+		}
+		for j, validator := range target.Validators {
+			if strings.TrimSpace(validator) == "" {
+				errs = append(errs, errors.New(fmt.Sprintf("%s.validators[%d]: is empty", field, j)))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isValidGRPCEndpoint checks if the endpoint is a host:port address, optionally prefixed by http(s)://
+func isValidGRPCEndpoint(endpoint string) bool {
+	var address = strings.TrimPrefix(strings.TrimPrefix(endpoint, "https://"), "http://")
+	host, port, err := net.SplitHostPort(address)
+	return err == nil && host != "" && port != ""
+}
+
+// isValidEndpoint checks if the endpoint is a http(s) or tcp url
+func isValidEndpoint(endpoint string) bool {
+	endpointUrl, err := url.Parse(endpoint)
+	if err != nil || endpointUrl.Host == "" {
+		return false
+	}
+	return endpointUrl.Scheme == "http" || endpointUrl.Scheme == "https" || endpointUrl.Scheme == "tcp"
+}
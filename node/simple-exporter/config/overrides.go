@@ -0,0 +1,247 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvPrefix prefixes the env names of the settings, ex. COSMONITOR_LISTEN_PORT for -listen_port
+const EnvPrefix = "COSMONITOR_"
+
+// legacyEnv are the unprefixed env names of the first releases, still read if the prefixed one is not set
+var legacyEnv = map[string]string{
+	"node_rpc": "NODE_RPC",
+}
+
+// setting is a configuration value that can be overridden by env or flag
+type setting struct {
+	name  string // flag name, the env name is the prefixed upper case one
+	usage string
+	set   func(c *Config, value string) error
+}
+
+// targetSetting is a setting of the single target defined by env or flags, requiring node_rpc
+type targetSetting struct {
+	name  string
+	usage string
+	set   func(t *Target, value string) error
+}
+
+var settings = []setting{
+	{"listen_port", "Port of the Prometheus metrics endpoint (default 9090)", func(c *Config, value string) error {
+		port, err := strconv.ParseUint(value, 10, 16)
+		c.ListenPort = uint(port)
+		return err
+	}},
+	{"poll_interval", "Metrics refresh interval while the websocket is not available (default 3s)", durationSetter(func(c *Config) *Duration { return &c.PollInterval })},
+	{"retry_delay", "Delay before retrying after a failed update (default 10s)", durationSetter(func(c *Config) *Duration { return &c.RetryDelay })},
+	{"query_timeout", "Timeout of the RPC and ABCI queries (default 10s)", durationSetter(func(c *Config) *Duration { return &c.QueryTimeout })},
+	{"ws_idle_timeout", "Max time without new blocks before considering the websocket dropped (default 60s)", durationSetter(func(c *Config) *Duration { return &c.WsIdleTimeout })},
+	{"ws_resubscribe_delay", "Time spent polling before subscribing again to the websocket (default 30s)", durationSetter(func(c *Config) *Duration { return &c.WsResubscribeDelay })},
+	{"uptime_window", "Number of recent blocks used to calculate the validator uptime (default 100)", func(c *Config, value string) error {
+		window, err := strconv.Atoi(value)
+		c.UptimeWindow = window
+		return err
+	}},
+	{"delegations_refresh", "Min interval between the validators delegations updates (default 5m)", durationSetter(func(c *Config) *Duration { return &c.DelegationsRefresh })},
+	{"consumer_refresh", "Min interval between the ICS provider validators and consumer keys updates (default 5m)", durationSetter(func(c *Config) *Duration { return &c.ConsumerRefresh })},
+	{"block_stale_threshold", "Max time since the latest block before considering the node blocks stale (default 1m)", durationSetter(func(c *Config) *Duration { return &c.BlockStaleThreshold })},
+}
+
+// targetSettings define the single target, replacing the config file targets once node_rpc is set
+var targetSettings = []targetSetting{
+	{"node_rpc", "RPC endpoint of the wanted node (ex. https://rpc.cosmos.network:443), replacing the config file targets", func(t *Target, value string) error {
+		t.RPC = value
+		return nil
+	}},
+	{"references", "Comma separated reference RPC endpoints of the -node_rpc chain (ex. other sentries), measuring the node lag, detecting the blocks divergences and telling a stuck node from a halted chain", func(t *Target, value string) error {
+		t.References = parseList(value)
+		return nil
+	}},
+	{"peers", "Comma separated node ids (or id@host:port addresses) of the persistent peers or sentries expected to be connected to -node_rpc", func(t *Target, value string) error {
+		t.Peers = parseList(value)
+		return nil
+	}},
+	{"chain_id", "Chain id of the -node_rpc node (default: fetched from the node)", func(t *Target, value string) error {
+		t.ChainID = value
+		return nil
+	}},
+	{"grpc", "gRPC endpoint of the -node_rpc node (ex. localhost:9090, https:// for TLS), used for the chain queries with the RPC", func(t *Target, value string) error {
+		t.GRPC = value
+		return nil
+	}},
+	{"rest", "REST (LCD) endpoint of the -node_rpc node (ex. http://localhost:1317), used for the chain queries with the RPC", func(t *Target, value string) error {
+		t.REST = value
+		return nil
+	}},
+	{"transport", "Transport of the -node_rpc chain queries, rpc, grpc or rest, falling back to the other ones (default rpc)", func(t *Target, value string) error {
+		t.Transport = value
+		return nil
+	}},
+	{"provider_rpc", "RPC endpoint of the ICS provider node, monitoring -node_rpc as a consumer chain of the -validators provider validators", func(t *Target, value string) error {
+		t.ProviderRPC = value
+		return nil
+	}},
+	{"consumer_id", "Consumer id of the -node_rpc chain on the ICS provider, since ICS v6 (default: the chain id)", func(t *Target, value string) error {
+		t.ConsumerID = value
+		return nil
+	}},
+	{"bech32_prefix", "Bech32 prefix of the -node_rpc chain (default: queried from the chain)", func(t *Target, value string) error {
+		t.Bech32Prefix = value
+		return nil
+	}},
+	{"validators", "Comma separated valoper/valcons addresses or monikers of the validators to monitor with -node_rpc (default: the node validator)", func(t *Target, value string) error {
+		t.Validators = parseList(value)
+		return nil
+	}},
+	{"accounts", "Comma separated account addresses (optionally alias=address) of the -node_rpc chain to monitor the balances of", func(t *Target, value string) error {
+		var accounts []Account
+		for _, entry := range parseList(value) {
+			var account = Account{Address: entry}
+			if alias, address, found := strings.Cut(entry, "="); found {
+				account = Account{Address: strings.TrimSpace(address), Alias: strings.TrimSpace(alias)}
+			}
+			accounts = append(accounts, account)
+		}
+		t.Accounts = accounts
+		return nil
+	}},
+	{"large_delegation_threshold", "Delegation change (base denom tokens) of the -node_rpc validators counted as large (default: disabled)", func(t *Target, value string) error {
+		threshold, err := strconv.ParseFloat(value, 64)
+		t.LargeDelegationThreshold = threshold
+		return err
+	}},
+	{"daemon_home", "Home of the -node_rpc node, to check the cosmovisor upgrades readiness (same as the cosmovisor env)", func(t *Target, value string) error {
+		t.DaemonHome = value
+		return nil
+	}},
+	{"daemon_name", "Binary name of the -node_rpc node in the cosmovisor folders (default: any executable)", func(t *Target, value string) error {
+		t.DaemonName = value
+		return nil
+	}},
+}
+
+func durationSetter(field func(c *Config) *Duration) func(c *Config, value string) error {
+	return func(c *Config, value string) error {
+		duration, err := time.ParseDuration(value)
+		*field(c) = Duration(duration)
+		return err
+	}
+}
+
+// singleTargetName is the name of the target defined by env or flags
+const singleTargetName = "default"
+
+// overrides holds the single target defined by env or flags, with the sources of its settings (ex. "flag -validators")
+type overrides struct {
+	target  *Target
+	sources []string
+}
+
+// set applies the value of the named setting from the given source, ignoring the unknown names
+func (o *overrides) set(c *Config, name string, value string, source string) error {
+	for _, s := range settings {
+		if s.name == name {
+			return s.set(c, value)
+		}
+	}
+	for _, s := range targetSettings {
+		if s.name == name {
+			if o.target == nil {
+				o.target = &Target{Name: singleTargetName}
+			}
+			o.sources = append(o.sources, source)
+			return s.set(o.target, value)
+		}
+	}
+	return nil
+}
+
+// apply replaces the config file targets with the single target, if node_rpc is set.
+// The target settings without node_rpc are rejected, the file target they apply to being unknown
+func (o *overrides) apply(c *Config) error {
+	if o.target == nil {
+		return nil
+	}
+	if o.target.RPC == "" {
+		return errors.New(fmt.Sprintf("%s: requires the node RPC (env %sNODE_RPC or flag -node_rpc), the config file targets are not overridden", strings.Join(o.sources, ", "), EnvPrefix))
+	}
+	c.Targets = []Target{*o.target}
+	return nil
+}
+
+// settingNames returns the names of the global settings and of the single target ones
+func settingNames() []string {
+	var names []string
+	for _, s := range settings {
+		names = append(names, s.name)
+	}
+	for _, s := range targetSettings {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+// RegisterFlags defines the settings flags on the flag set
+func RegisterFlags(flags *flag.FlagSet) {
+	for _, s := range settings {
+		flags.String(s.name, "", s.usage)
+	}
+	for _, s := range targetSettings {
+		flags.String(s.name, "", s.usage)
+	}
+}
+
+// applyEnv overrides the settings defined in the env
+func (c *Config) applyEnv(o *overrides) error {
+	for _, name := range settingNames() {
+		var envName = EnvPrefix + strings.ToUpper(name)
+		var value = os.Getenv(envName)
+		if value == "" && legacyEnv[name] != "" {
+			envName = legacyEnv[name]
+			value = os.Getenv(envName)
+		}
+		if value == "" {
+			continue
+		}
+		err := o.set(c, name, value, "env "+envName)
+		if err != nil {
+			return errors.New(fmt.Sprintf("env %s: invalid value '%s'", envName, value))
+		}
+	}
+	return nil
+}
+
+// applyFlags overrides the settings of the set flags
+func (c *Config) applyFlags(o *overrides, flags *flag.FlagSet) error {
+	if flags == nil {
+		return nil
+	}
+	var err error
+	flags.Visit(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+		if o.set(c, f.Name, f.Value.String(), "flag -"+f.Name) != nil {
+			err = errors.New(fmt.Sprintf("flag -%s: invalid value '%s'", f.Name, f.Value.String()))
+		}
+	})
+	return err
+}
+
+// parseList splits a comma separated list, ignoring the empty entries
+func parseList(value string) []string {
+	var list []string
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry != "" {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
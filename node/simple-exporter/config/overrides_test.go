@@ -0,0 +1,90 @@
+package config
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+var fileTargets = []Target{{Name: "node0", RPC: "http://node0:26657"}, {Name: "node1", RPC: "http://node1:26657"}}
+
+func TestOverrides(t *testing.T) {
+	var tests = []struct {
+		name        string
+		env         map[string]string
+		flags       []string
+		wantTargets []Target
+		wantErr     string
+	}{
+		{
+			name:        "global settings keep the file targets",
+			env:         map[string]string{"COSMONITOR_UPTIME_WINDOW": "200"},
+			flags:       []string{"-listen_port", "9191"},
+			wantTargets: fileTargets,
+		},
+		{
+			name:        "unprefixed env is ignored",
+			env:         map[string]string{"CHAIN_ID": "other-1", "DAEMON_HOME": "/root/.gaia"},
+			wantTargets: fileTargets,
+		},
+		{
+			name:        "node rpc replaces the file targets",
+			env:         map[string]string{"COSMONITOR_NODE_RPC": "http://localhost:26657", "COSMONITOR_VALIDATORS": "val1,val2"},
+			flags:       []string{"-chain_id", "cosmoshub-4"},
+			wantTargets: []Target{{Name: singleTargetName, RPC: "http://localhost:26657", ChainID: "cosmoshub-4", Validators: []string{"val1", "val2"}}},
+		},
+		{
+			name:        "legacy node rpc env",
+			env:         map[string]string{"NODE_RPC": "http://localhost:26657"},
+			wantTargets: []Target{{Name: singleTargetName, RPC: "http://localhost:26657"}},
+		},
+		{
+			name:    "target settings without node rpc",
+			env:     map[string]string{"COSMONITOR_DAEMON_HOME": "/root/.gaia"},
+			flags:   []string{"-validators", "val1"},
+			wantErr: "env COSMONITOR_DAEMON_HOME, flag -validators: requires the node RPC",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for name, value := range test.env {
+				t.Setenv(name, value)
+			}
+			var flags = flag.NewFlagSet("test", flag.ContinueOnError)
+			RegisterFlags(flags)
+			err := flags.Parse(test.flags)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var config = Default()
+			config.Targets = append([]Target{}, fileTargets...)
+			var overrides = &overrides{}
+			err = config.applyEnv(overrides)
+			if err == nil {
+				err = config.applyFlags(overrides, flags)
+			}
+			if err == nil {
+				err = overrides.apply(config)
+			}
+			if test.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), test.wantErr) {
+					t.Fatalf("got error %v, want '%s'", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(config.Targets) != len(test.wantTargets) {
+				t.Fatalf("got targets %+v, want %+v", config.Targets, test.wantTargets)
+			}
+			for i, target := range config.Targets {
+				var want = test.wantTargets[i]
+				if target.Name != want.Name || target.RPC != want.RPC || target.ChainID != want.ChainID || strings.Join(target.Validators, ",") != strings.Join(want.Validators, ",") {
+					t.Errorf("got target %+v, want %+v", target, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"github.com/cosmos/cosmos-sdk/types"
+	stakingTypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"sort"
+)
+
+// activeSet holds the staking validators candidates to the active set, sorted by tokens
+type activeSet struct {
+	candidates    []stakingTypes.Validator
+	maxValidators int
+}
+
+// newActiveSet sorts the not jailed validators by tokens, as the staking module does at the end of each block
+func newActiveSet(validators *[]stakingTypes.Validator, maxValidators uint32) *activeSet {
+	var candidates []stakingTypes.Validator
+	for _, validator := range *validators {
+		if !validator.Jailed {
+			candidates = append(candidates, validator)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Tokens.GT(candidates[j].Tokens)
+	})
+	return &activeSet{
+		candidates:    candidates,
+		maxValidators: int(maxValidators),
+	}
+}
+
+// lastActiveTokens returns the tokens of the last validator in the active set
+func (s *activeSet) lastActiveTokens() types.Int {
+	var last = s.maxValidators
+	if len(s.candidates) < last {
+		last = len(s.candidates)
+	}
+	if last == 0 {
+		return types.ZeroInt()
+	}
+	return s.candidates[last-1].Tokens
+}
+
+// bestInactiveTokens returns the tokens of the first validator out of the active set, zero if there are not enough candidates
+func (s *activeSet) bestInactiveTokens() types.Int {
+	if len(s.candidates) <= s.maxValidators {
+		return types.ZeroInt()
+	}
+	return s.candidates[s.maxValidators].Tokens
+}
+
+// position returns the position by tokens of the validator, false if it is not a candidate (jailed)
+func (s *activeSet) position(operatorAddress string) (int, bool) {
+	for i, candidate := range s.candidates {
+		if candidate.OperatorAddress == operatorAddress {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+// updateActiveSet updates the active set cutoff metrics and the distance of the validators from it
+func (m *Monitor) updateActiveSet(validators []*monitoredValidator, stakingValidators *[]stakingTypes.Validator, params *stakingTypes.Params) {
+	var set = newActiveSet(stakingValidators, params.MaxValidators)
+	var lastActiveTokens = set.lastActiveTokens()
+	m.metrics.UpdateActiveSet(params.MaxValidators, lastActiveTokens, set.bestInactiveTokens())
+
+	for _, validator := range validators {
+		if validator.validator == nil {
+			continue
+		}
+		var labels = validator.labels()
+		position, ok := set.position(validator.validator.OperatorAddress)
+		if !ok {
+			m.metrics.DeleteStakePosition(labels)
+			continue
+		}
+		// negative if the validator is out of the active set
+		m.metrics.UpdateStakePosition(labels, position, validator.validator.Tokens.Sub(lastActiveTokens))
+	}
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"github.com/cosmos/cosmos-sdk/types"
+	stakingTypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"testing"
+)
+
+func TestNewActiveSet(t *testing.T) {
+	var validator = func(operatorAddress string, tokens int64, jailed bool) stakingTypes.Validator {
+		return stakingTypes.Validator{OperatorAddress: operatorAddress, Tokens: types.NewInt(tokens), Jailed: jailed}
+	}
+	var validators = []stakingTypes.Validator{
+		validator("low", 100, false),
+		validator("jailed", 1000, true),
+		validator("high", 500, false),
+		validator("middle", 300, false),
+		validator("tied", 300, false),
+	}
+
+	var tests = []struct {
+		name             string
+		validators       []stakingTypes.Validator
+		maxValidators    uint32
+		wantOrder        []string
+		wantLast         int64
+		wantBestInactive int64
+	}{
+		{
+			name:             "jailed candidates excluded",
+			validators:       validators,
+			maxValidators:    2,
+			wantOrder:        []string{"high", "middle", "tied", "low"},
+			wantLast:         300,
+			wantBestInactive: 300,
+		},
+		{
+			name:             "last active tokens at the cutoff",
+			validators:       validators,
+			maxValidators:    3,
+			wantOrder:        []string{"high", "middle", "tied", "low"},
+			wantLast:         300,
+			wantBestInactive: 100,
+		},
+		{
+			name:             "as many candidates as max validators",
+			validators:       validators,
+			maxValidators:    4,
+			wantOrder:        []string{"high", "middle", "tied", "low"},
+			wantLast:         100,
+			wantBestInactive: 0,
+		},
+		{
+			name:             "fewer candidates than max validators",
+			validators:       validators,
+			maxValidators:    100,
+			wantOrder:        []string{"high", "middle", "tied", "low"},
+			wantLast:         100,
+			wantBestInactive: 0,
+		},
+		{
+			name:             "all jailed",
+			validators:       []stakingTypes.Validator{validator("jailed", 1000, true)},
+			maxValidators:    100,
+			wantOrder:        nil,
+			wantLast:         0,
+			wantBestInactive: 0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var set = newActiveSet(&test.validators, test.maxValidators)
+			if len(set.candidates) != len(test.wantOrder) {
+				t.Fatalf("got %d candidates, want %d", len(set.candidates), len(test.wantOrder))
+			}
+			// the tied candidates keep their staking order
+			for i, operatorAddress := range test.wantOrder {
+				if position, ok := set.position(operatorAddress); !ok || position != i+1 {
+					t.Errorf("got %s at position %d (%t), want %d", operatorAddress, position, ok, i+1)
+				}
+			}
+			if _, ok := set.position("jailed"); ok {
+				t.Error("got a position for the jailed validator")
+			}
+			if !set.lastActiveTokens().Equal(types.NewInt(test.wantLast)) || !set.bestInactiveTokens().Equal(types.NewInt(test.wantBestInactive)) {
+				t.Errorf("got last active tokens %s and best inactive tokens %s, want %d and %d", set.lastActiveTokens(), set.bestInactiveTokens(), test.wantLast, test.wantBestInactive)
+			}
+		})
+	}
+}
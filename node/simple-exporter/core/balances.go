@@ -0,0 +1,76 @@
+package core
+
+import (
+	"fmt"
+	"github.com/cosmos/cosmos-sdk/types"
+	"simple-exporter/abci"
+	"simple-exporter/config"
+)
+
+// operatorAlias is the alias of the validators operator accounts
+const operatorAlias = "operator"
+
+// updateBalances updates the balances of the validators operator accounts and of the configured accounts
+func (m *Monitor) updateBalances(validators []*monitoredValidator, bech32Prefix string) {
+	var accounts []config.Account
+	for _, validator := range validators {
+		// the operator account is available only from the staking validator
+		if validator.validator == nil {
+			continue
+		}
+		address, err := operatorAccountAddress(validator.validator.OperatorAddress, bech32Prefix)
+		if err != nil {
+			m.logger.Println(fmt.Sprintf("Cannot get the operator account of '%s' (%s)", validator.validator.OperatorAddress, err.Error()))
+			continue
+		}
+		accounts = append(accounts, config.Account{Address: address, Alias: operatorAlias, Spendable: true})
+	}
+	accounts = append(accounts, m.target.Accounts...)
+
+	// a failing account doesn't stop the other metrics update
+	for _, account := range accounts {
+		balances, err := abci.GetAllBalances(m.querier, account.Address)
+		if err != nil {
+			m.logger.Println(fmt.Sprintf("Cannot get the balances of '%s' (%s)", account.Address, err.Error()))
+			continue
+		}
+		m.metrics.UpdateAccountBalances(account.Address, account.Alias, m.withDrainedDenoms(account, balances))
+
+		// the spendable balances are not available before Cosmos-Sdk v0.46
+		if !account.Spendable || m.versions.sdkBefore(0, 46) {
+			continue
+		}
+		spendable, err := abci.GetSpendableBalances(m.querier, account.Address)
+		if err != nil {
+			m.logger.Println(fmt.Sprintf("Cannot get the spendable balances of '%s' (%s)", account.Address, err.Error()))
+			continue
+		}
+		m.metrics.UpdateAccountSpendableBalances(account.Address, account.Alias, m.withDrainedDenoms(account, spendable))
+	}
+}
+
+// withDrainedDenoms adds a zero balance of the configured denoms and of the denoms seen before, not returned once
+// the account doesn't hold them anymore, keeping their series instead of removing them
+func (m *Monitor) withDrainedDenoms(account config.Account, coins types.Coins) types.Coins {
+	denoms, ok := m.denoms[account.Address]
+	if !ok {
+		denoms = make(map[string]bool)
+		m.denoms[account.Address] = denoms
+	}
+	for _, denom := range account.Denoms {
+		denoms[denom] = true
+	}
+	var held = make(map[string]bool)
+	for _, coin := range coins {
+		held[coin.Denom] = true
+		denoms[coin.Denom] = true
+	}
+
+	var balances = append(types.Coins{}, coins...)
+	for denom := range denoms {
+		if !held[denom] {
+			balances = append(balances, types.Coin{Denom: denom, Amount: types.ZeroInt()})
+		}
+	}
+	return balances
+}
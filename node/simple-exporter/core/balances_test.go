@@ -0,0 +1,72 @@
+package core
+
+import (
+	"context"
+	sdkTypes "github.com/cosmos/cosmos-sdk/types"
+	bankTypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"simple-exporter/config"
+	"simple-exporter/types"
+	"testing"
+)
+
+// balancesClient answers the balances queries with the given coins, omitting the zero ones as the bank module
+type balancesClient struct {
+	coins sdkTypes.Coins
+}
+
+func (c *balancesClient) Query(_ context.Context, _ string, _ []byte) (*types.ResultABCIQuery, error) {
+	value, err := (&bankTypes.QueryAllBalancesResponse{Balances: c.coins}).Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return &types.ResultABCIQuery{Response: types.ResponseQuery{Value: value}}, nil
+}
+
+func TestUpdateBalancesDrained(t *testing.T) {
+	var client = &balancesClient{}
+	var monitor = newTestMonitor(client)
+	monitor.target.Accounts = []config.Account{{Address: "cosmos1feeder", Alias: "feeder", Denoms: []string{"uatom"}}}
+	var registry = prom.NewRegistry()
+	monitor.collectors.Register(registry)
+
+	var tests = []struct {
+		name  string
+		coins sdkTypes.Coins
+		want  map[string]float64 // balances by denom, the missing denoms not being exported
+	}{
+		{
+			name:  "configured denom never held",
+			coins: sdkTypes.NewCoins(),
+			want:  map[string]float64{"uatom": 0},
+		},
+		{
+			name:  "held denoms",
+			coins: sdkTypes.NewCoins(sdkTypes.NewInt64Coin("uatom", 1000), sdkTypes.NewInt64Coin("ibc/fees", 50)),
+			want:  map[string]float64{"uatom": 1000, "ibc/fees": 50},
+		},
+		{
+			name:  "drained denom seen before",
+			coins: sdkTypes.NewCoins(sdkTypes.NewInt64Coin("uatom", 400)),
+			want:  map[string]float64{"uatom": 400, "ibc/fees": 0},
+		},
+		{
+			name:  "drained account",
+			coins: sdkTypes.NewCoins(),
+			want:  map[string]float64{"uatom": 0, "ibc/fees": 0},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client.coins = test.coins
+			monitor.updateBalances(nil, "cosmos")
+			for _, denom := range []string{"uatom", "ibc/fees"} {
+				value, ok := gaugeValue(t, registry, "account_balance", map[string]string{"address": "cosmos1feeder", "alias": "feeder", "denom": denom})
+				want, wantOk := test.want[denom]
+				if ok != wantOk || value != want {
+					t.Errorf("got %s balance %f (exported %t), want %f (exported %t)", denom, value, ok, want, wantOk)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"fmt"
+	bech322 "github.com/cosmos/cosmos-sdk/types/bech32"
+	ctypes "github.com/tendermint/tendermint/types"
+	"simple-exporter/abci"
+	"simple-exporter/rpc"
+	"slices"
+	"time"
+)
+
+// consumerCache holds the provider validators resolved for a consumer chain, refreshed every consumer refresh interval
+// instead of querying the provider validators and key assignments on each block
+type consumerCache struct {
+	lastUpdate time.Time
+	validators []consumerValidator
+	optedIn    []string // valcons addresses of the provider validators opted in the consumer chain, nil if not available
+}
+
+// consumerValidator holds a provider validator and its consensus address on the consumer chain
+type consumerValidator struct {
+	provider    *monitoredValidator
+	consAddress ctypes.Address // address of the assigned consumer key, the provider one without an assigned key
+}
+
+// resolveConsumerValidators resolves the monitored validators of an ICS consumer chain from the configured provider validators,
+// following their consumer key assignments
+func (m *Monitor) resolveConsumerValidators(consValidators *[]*ctypes.Validator, bech32Prefix string) ([]*monitoredValidator, error) {
+	if m.consumer == nil || time.Since(m.consumer.lastUpdate) >= m.config.ConsumerRefresh.Duration() {
+		cache, err := m.fetchConsumerValidators()
+		switch {
+		case err == nil:
+			m.consumer = cache
+		case m.consumer == nil:
+			return nil, err
+		default:
+			// the provider may be temporarily unavailable, the key assignments rarely change
+			m.logger.Println(fmt.Sprintf("Cannot refresh the provider validators (%s), using the ones of %s", err.Error(), m.consumer.lastUpdate.Format(time.RFC3339)))
+		}
+	}
+
+	var resolved []*monitoredValidator
+	for _, consumerValidator := range m.consumer.validators {
+		var consValidator = getConsValidatorFromAddress(consumerValidator.consAddress.String(), consValidators)
+		validator, err := newMonitoredValidator(consumerValidator.consAddress, consValidator, nil, bech32Prefix)
+		if err != nil {
+			return nil, err
+		}
+		validator.moniker = consumerValidator.provider.moniker
+		validator.valoper = consumerValidator.provider.valoper
+		if m.consumer.optedIn != nil {
+			m.metrics.UpdateConsumerOptedIn(validator.labels(), slices.Contains(m.consumer.optedIn, consumerValidator.provider.valcons))
+		}
+		resolved = append(resolved, validator)
+	}
+	return resolved, nil
+}
+
+// fetchConsumerValidators resolves the configured provider validators and their consumer addresses from the provider
+func (m *Monitor) fetchConsumerValidators() (*consumerCache, error) {
+	var providerQuerier = abci.NewRPCClient(m.provider)
+	providerConsValidators, err := rpc.GetValidators(m.provider)
+	if err != nil {
+		return nil, err
+	}
+	providerValidators, err := abci.GetValidators(providerQuerier)
+	if err != nil {
+		return nil, err
+	}
+	providerPrefix, err := abci.GetBech32Prefix(providerQuerier)
+	if err != nil {
+		return nil, err
+	}
+
+	// the opt-in is available only with the partial set security (since ICS v5)
+	var consumer = abci.ConsumerChain{ChainID: m.chainID, ConsumerID: m.target.ConsumerID}
+	var cache = &consumerCache{lastUpdate: time.Now()}
+	cache.optedIn, err = abci.GetConsumerOptedInValidators(providerQuerier, consumer)
+	if err != nil {
+		m.logger.Println(fmt.Sprintf("Cannot get the consumer opted in validators (%s)", err.Error()))
+		cache.optedIn = nil
+	}
+
+	for _, identifier := range m.target.Validators {
+		providerValidator, err := resolveValidator(identifier, providerConsValidators, providerValidators, providerPrefix)
+		if err != nil {
+			return nil, err
+		}
+		if providerValidator == nil || providerValidator.validator == nil {
+			m.logger.Println(fmt.Sprintf("Cannot find the provider validator '%s'", identifier))
+			continue
+		}
+
+		consAddress, err := consumerConsAddress(providerQuerier, consumer, providerValidator)
+		if err != nil {
+			return nil, err
+		}
+		cache.validators = append(cache.validators, consumerValidator{provider: providerValidator, consAddress: consAddress})
+	}
+	return cache, nil
+}
+
+// consumerConsAddress returns the consensus address of a provider validator on the consumer chain, from its assigned consumer key if any
+func consumerConsAddress(providerQuerier abci.Client, consumer abci.ConsumerChain, providerValidator *monitoredValidator) (ctypes.Address, error) {
+	consumerAddress, err := abci.GetValidatorConsumerAddr(providerQuerier, consumer, providerValidator.valcons)
+	if err != nil {
+		return nil, err
+	}
+	// without an assigned key, the validator uses its provider one
+	if consumerAddress == "" {
+		consumerAddress = providerValidator.valcons
+	}
+	_, consAddress, err := bech322.DecodeAndConvert(consumerAddress)
+	if err != nil {
+		return nil, err
+	}
+	return consAddress, nil
+}
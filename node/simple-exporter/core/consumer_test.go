@@ -0,0 +1,71 @@
+package core
+
+import (
+	"errors"
+	"simple-exporter/config"
+	"simple-exporter/rpc"
+	"simple-exporter/testutil"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConsumerRefresh(t *testing.T) {
+	// the provider validators didn't assign a consumer key, validating the consumer chain with their provider one
+	var keyQueries atomic.Int64
+	var providerDown atomic.Bool
+	var providerErr = func() error {
+		if providerDown.Load() {
+			return errors.New("provider not available")
+		}
+		return nil
+	}
+	var provider = testutil.NewFixtureRPCWithQueries(t, testutil.Chains[2], map[string]testutil.Query{
+		"/interchain_security.ccv.provider.v1.Query/QueryValidatorConsumerAddr": func(data []byte) ([]byte, error) {
+			keyQueries.Add(1)
+			return nil, providerErr()
+		},
+		"/interchain_security.ccv.provider.v1.Query/QueryConsumerChainOptedInValidators": func(data []byte) ([]byte, error) {
+			return nil, providerErr()
+		},
+	})
+	consValidators, err := rpc.GetValidators(provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var monitor = newTestMonitor(nil)
+	monitor.config.ConsumerRefresh = config.Duration(time.Hour)
+	monitor.target.Validators = []string{"cosmosvaloper1jzlh2amp06kkxm0e6gthzmjz4z03ugc0skwnx0"}
+	monitor.provider = provider
+	var resolve = func() {
+		t.Helper()
+		validators, err := monitor.resolveConsumerValidators(consValidators, "cosmos")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(validators) != 1 || validators[0].moniker != "Chorus One" || validators[0].consValidator == nil {
+			t.Fatalf("got the consumer validators %+v", validators)
+		}
+	}
+
+	// the key assignments are cached until the refresh interval
+	resolve()
+	resolve()
+	if keyQueries.Load() != 1 {
+		t.Errorf("got %d key assignment queries, want 1", keyQueries.Load())
+	}
+	monitor.consumer.lastUpdate = time.Now().Add(-2 * time.Hour)
+	resolve()
+	if keyQueries.Load() != 2 {
+		t.Errorf("got %d key assignment queries after the refresh interval, want 2", keyQueries.Load())
+	}
+
+	// the cached validators are kept while the provider is not available
+	providerDown.Store(true)
+	monitor.consumer.lastUpdate = time.Now().Add(-2 * time.Hour)
+	resolve()
+	if keyQueries.Load() != 3 {
+		t.Errorf("got %d key assignment queries, want 3", keyQueries.Load())
+	}
+}
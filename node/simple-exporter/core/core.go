@@ -3,58 +3,265 @@ package core
 import (
 	"errors"
 	"fmt"
-	bech322 "github.com/cosmos/cosmos-sdk/types/bech32"
+	slashingTypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
 	stakingTypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 	tmhttp "github.com/tendermint/tendermint/rpc/client/http"
 	ctypes "github.com/tendermint/tendermint/types"
 	"log"
 	"simple-exporter/abci"
+	"simple-exporter/config"
 	"simple-exporter/prometheus"
 	"simple-exporter/rpc"
 	"time"
 )
 
-func ListenWS(rpcAddr string) {
-	// create the ABCI client
-	client, err := tmhttp.New(rpcAddr, "")
+// Monitor holds the monitored target and its validators state across the metrics updates
+type Monitor struct {
+	target      config.Target
+	config      *config.Config
+	collectors  *prometheus.Metrics            // collectors of the target metrics
+	chainID     string                         // target chain id, fetched from the node if not configured
+	metrics     *prometheus.Target             // target metrics, available once the chain id is known
+	querier     abci.Client                    // client of the chain queries, through the target transports
+	client      *tmhttp.HTTP                   // target RPC client, nil until connected
+	grpc        *abci.GRPCClient               // gRPC client, nil if the target gRPC endpoint is not configured
+	signatures  map[string]*SignatureTracker   // validators signatures trackers by valcons address
+	delegations map[string]*delegationsTracker // validators delegations trackers by valcons address
+	denoms      map[string]map[string]bool     // denoms seen in the accounts balances by address, exported as 0 once drained
+	upgradePlan string                         // name of the last seen upgrade plan, to detect when it is applied
+	provider    *tmhttp.HTTP                   // ICS provider RPC client, nil if the target is not a consumer chain
+	consumer    *consumerCache                 // provider validators of the consumer chain, nil until resolved
+	references  []*tmhttp.HTTP                 // reference nodes RPC clients, in the target references order
+	divergences map[string]*divergenceTracker  // blocks comparisons with the reference nodes by endpoint
+	versions    *nodeVersions                  // node software versions, nil until detected
+	blockTime   time.Time                      // node latest block time, to update the blocks staleness while the node status is not available
+	probe       bool                           // single /probe scrape, skipping the heavy collectors and the per-block counters
+	logger      *log.Logger
+}
+
+// NewMonitor creates a Monitor of the given target node and validators
+func NewMonitor(target config.Target, config *config.Config, collectors *prometheus.Metrics) *Monitor {
+	var monitor = &Monitor{
+		target:      target,
+		config:      config,
+		collectors:  collectors,
+		chainID:     target.ChainID,
+		signatures:  make(map[string]*SignatureTracker),
+		delegations: make(map[string]*delegationsTracker),
+		denoms:      make(map[string]map[string]bool),
+		divergences: make(map[string]*divergenceTracker),
+		logger:      log.New(log.Writer(), fmt.Sprintf("[%s] ", target.Name), log.LstdFlags),
+	}
+	if target.ChainID != "" {
+		monitor.metrics = collectors.NewTarget(target.ChainID, target.Name)
+	}
+	return monitor
+}
+
+// ListenWS subscribes to the node new blocks, refreshing the metrics on each block.
+// If the websocket is not available, it falls back to polling and then resubscribes
+func (m *Monitor) ListenWS() {
+	// create the RPC and ABCI clients
+	client, err := m.connect()
 	if err != nil {
-		log.Println(err.Error())
+		m.logger.Println(err.Error())
+		m.disconnect()
+		return
 	}
 
 	defer func() {
 		err = client.Stop()
 		if err != nil {
-			log.Println(err.Error())
+			m.logger.Println(err.Error())
 		}
+		m.disconnect()
 	}()
 
+	// the chain id is needed to label the metrics
+	for m.metrics == nil {
+		err = m.resolveChainID(client)
+		if err != nil {
+			m.logger.Println(fmt.Sprintf("Cannot get the chain id (%s), retrying in %s", err.Error(), m.config.RetryDelay.Duration()))
+			time.Sleep(m.config.RetryDelay.Duration())
+		}
+	}
+
+	for {
+		subscription, err := subscribeNewBlocks(m.target.RPC)
+		if err != nil {
+			m.logger.Println(fmt.Sprintf("Cannot subscribe to new blocks (%s), polling for %s", err.Error(), m.config.WsResubscribeDelay.Duration()))
+			m.pollMetrics(client, m.config.WsResubscribeDelay.Duration())
+			continue
+		}
+		m.logger.Println("Subscribed to new blocks")
+
+		m.listenBlocks(client, subscription)
+		subscription.close()
+
+		m.logger.Println(fmt.Sprintf("No new blocks received in %s, polling for %s", m.config.WsIdleTimeout.Duration(), m.config.WsResubscribeDelay.Duration()))
+		m.pollMetrics(client, m.config.WsResubscribeDelay.Duration())
+	}
+}
+
+// connect creates the RPC client and the chain queries client, using the target transports in order of preference.
+// The clients must be released with disconnect, also on error
+func (m *Monitor) connect() (*tmhttp.HTTP, error) {
+	var err error
+	m.client, err = rpc.NewClient(m.target.RPC, m.config.QueryTimeout.Duration())
+	if err != nil {
+		return nil, err
+	}
+	var client = m.client
+	m.references = nil
+	for _, reference := range m.target.References {
+		referenceClient, err := rpc.NewClient(reference, m.config.QueryTimeout.Duration())
+		if err != nil {
+			return nil, err
+		}
+		m.references = append(m.references, referenceClient)
+	}
+	if m.target.ProviderRPC != "" {
+		m.provider, err = rpc.NewClient(m.target.ProviderRPC, m.config.QueryTimeout.Duration())
+		if err != nil {
+			return nil, err
+		}
+	}
+	var clients = map[string]abci.Client{
+		config.TransportRPC: abci.NewRPCClient(client),
+	}
+	if m.target.GRPC != "" {
+		m.grpc, err = abci.NewGRPCClient(m.target.GRPC)
+		if err != nil {
+			return nil, err
+		}
+		clients[config.TransportGRPC] = m.grpc
+	}
+	if m.target.REST != "" {
+		clients[config.TransportREST] = abci.NewRESTClient(m.target.REST)
+	}
+	if len(clients) == 1 {
+		m.querier = clients[config.TransportRPC]
+		return client, nil
+	}
+
+	m.querier = abci.NewFallbackClient(orderTransports(m.target.Transport, clients)...)
+	return client, nil
+}
+
+// orderTransports orders the query clients by transport, the preferred one first, then the other ones
+func orderTransports(preferred string, clients map[string]abci.Client) []abci.Client {
+	if preferred == "" {
+		preferred = config.TransportRPC
+	}
+	var ordered = []abci.Client{clients[preferred]}
+	for _, transport := range []string{config.TransportRPC, config.TransportGRPC, config.TransportREST} {
+		if transport != preferred && clients[transport] != nil {
+			ordered = append(ordered, clients[transport])
+		}
+	}
+	return ordered
+}
+
+// disconnect releases the RPC clients and closes the gRPC connection, if any
+func (m *Monitor) disconnect() {
+	for _, client := range append([]*tmhttp.HTTP{m.client, m.provider}, m.references...) {
+		if client != nil {
+			rpc.CloseClient(client)
+		}
+	}
+	m.client = nil
+	m.provider = nil
+	m.references = nil
+
+	if m.grpc == nil {
+		return
+	}
+	err := m.grpc.Close()
+	if err != nil {
+		m.logger.Println(err.Error())
+	}
+	m.grpc = nil
+}
+
+// listenBlocks refreshes the metrics on each new block, skipping the blocks received during a refresh, until the subscription stops receiving events
+func (m *Monitor) listenBlocks(client *tmhttp.HTTP, subscription *blockSubscription) {
+	var lastHeight int64 = 0
+	for {
+		height, ok := subscription.next(m.config.WsIdleTimeout.Duration())
+		if !ok {
+			return
+		}
+		// a height is refreshed only once
+		if height <= lastHeight {
+			continue
+		}
+		lastHeight = height
+		_ = m.refreshMetrics(client)
+	}
+}
+
+// pollMetrics refreshes the metrics every poll interval for the given duration
+func (m *Monitor) pollMetrics(client *tmhttp.HTTP, duration time.Duration) {
 	var retry = 0
-	const retryTimeout = 10
-	for true {
-		time.Sleep(3 * time.Second)
-		var err = UpdateMetrics(client)
+	var deadline = time.Now().Add(duration)
+	for time.Now().Before(deadline) {
+		time.Sleep(m.config.PollInterval.Duration())
+		var err = m.refreshMetrics(client)
 		if err != nil {
-			log.Println(err.Error())
-			prometheus.UpdateNodeInfo(false, "", "", "")
 			retry += 1
-			time.Sleep(retryTimeout * time.Second)
-			log.Println(fmt.Sprintf("Error Updating metrics, retrying in %ds (attempt #%d)", retryTimeout, retry))
-			continue
-		} else {
-			prometheus.DeleteNodeInfo("", "", "")
+			m.logger.Println(fmt.Sprintf("Error Updating metrics, retrying in %s (attempt #%d)", m.config.RetryDelay.Duration(), retry))
+			time.Sleep(m.config.RetryDelay.Duration())
 		}
-		log.Println("Metrics updated correctly")
 	}
 }
 
-func UpdateMetrics(client *tmhttp.HTTP) error {
+// refreshMetrics updates the metrics, tracking the node online status
+func (m *Monitor) refreshMetrics(client *tmhttp.HTTP) error {
+	var err = m.UpdateMetrics(client)
+	if err != nil {
+		m.logger.Println(err.Error())
+		m.metrics.UpdateNodeInfo(false, "", "", "")
+		return err
+	}
+	m.metrics.DeleteNodeInfo("", "", "")
+	m.logger.Println("Metrics updated correctly")
+	return nil
+}
+
+// resolveChainID fetches the target chain id from the node, creating the target metrics
+func (m *Monitor) resolveChainID(client *tmhttp.HTTP) error {
+	nodeInfo, err := rpc.GetNodeInfo(client)
+	if err != nil {
+		return err
+	}
+	m.chainID = nodeInfo.NodeInfo.Network
+	m.metrics = m.collectors.NewTarget(m.chainID, m.target.Name)
+	return nil
+}
+
+// UpdateMetrics updates the target and its validators metrics
+func (m *Monitor) UpdateMetrics(client *tmhttp.HTTP) error {
 	// get the node info
 	nodeInfo, err := rpc.GetNodeInfo(client)
+	if err != nil {
+		m.updateUnavailableSyncInfo()
+		return err
+	}
+	m.logger.Println(fmt.Sprintf("Fetched node '%s' info", nodeInfo.NodeInfo.Moniker))
+	m.logger.Println(fmt.Sprintf("Network: '%s'", nodeInfo.NodeInfo.Network))
+	if nodeInfo.NodeInfo.Network != m.chainID {
+		return errors.New(fmt.Sprintf("node network '%s' doesn't match the target chain id '%s'", nodeInfo.NodeInfo.Network, m.chainID))
+	}
+
+	// update the sync info, before the queries that may fail on a stuck node
+	m.updateSyncInfo(client, nodeInfo)
+	m.updatePeers(client)
+
+	// detect the node versions, adapting the queries to the chain
+	err = m.updateVersions(client, nodeInfo)
 	if err != nil {
 		return err
 	}
-	log.Println(fmt.Sprintf("Fetched node '%s' info", nodeInfo.NodeInfo.Moniker))
-	log.Println(fmt.Sprintf("Network: '%s'", nodeInfo.NodeInfo.Network))
 
 	// get the Validators from Consensus
 	consValidators, err := rpc.GetValidators(client)
@@ -62,90 +269,208 @@ func UpdateMetrics(client *tmhttp.HTTP) error {
 		return err
 	}
 
-	prometheus.UpdateNodeInfo(true, nodeInfo.NodeInfo.Network, nodeInfo.NodeInfo.Moniker, string(nodeInfo.NodeInfo.DefaultNodeID))
-
-	// get the wanted Consensus validator
-	var consValidator, rank = getConsValidatorFromAddress(nodeInfo.ValidatorInfo.Address.String(), consValidators)
-	if consValidator == nil {
-		log.Println(fmt.Sprintf("Node '%s' is not a Validator", nodeInfo.NodeInfo.Moniker))
-		return nil
-	}
+	m.metrics.UpdateNodeInfo(true, nodeInfo.NodeInfo.Network, nodeInfo.NodeInfo.Moniker, string(nodeInfo.NodeInfo.DefaultNodeID))
 
 	// set the generic Validators Consensus info (that don't need Validators ABCI Query)
-	prometheus.UpdateTotalVotingPower(uint64(calculateTotalVotingPower(consValidators)))
-	prometheus.UpdateVotingPower(uint64(consValidator.VotingPower))
-	prometheus.UpdateRank(rank)
+	m.metrics.UpdateTotalVotingPower(uint64(calculateTotalVotingPower(consValidators)))
+	m.metrics.UpdatePowerDistribution(nakamotoCoefficient(consValidators), topValidatorsShare(consValidators, topValidatorsRatio))
+
+	// retrieve chain Bech32 Prefix from the config or the ABCI endpoint (since v0.46)
+	var bech32Prefix = m.target.Bech32Prefix
+	if bech32Prefix == "" {
+		if !m.versions.sdkBefore(0, 46) {
+			bech32Prefix, _ = abci.GetBech32Prefix(m.querier)
+		}
+		if bech32Prefix == "" {
+			// chain not supported, try getting an address
+			bech32Prefix, err = abci.GetBech32PrefixFromAuthAccounts(m.querier)
+			if err != nil {
+				return err
+			}
+		}
+	}
 
-	// retrieve chain Bech32 Prefix from the ABCI endpoint (since v0.46)
-	bech32Prefix, err := abci.GetBech32Prefix(client)
+	// update the slashing params
+	slashingParams, err := abci.GetSlashingParams(m.querier)
 	if err != nil {
-		// chain ot supported, try getting an address
-		bech32Prefix, err = abci.GetBech32PrefixFromAuthAccounts(client)
+		return err
+	}
+	m.metrics.UpdateSlashingParams(slashingParams)
+
+	// the upgrade plan, the governance, the delegations and the balances are skipped by the probes, too heavy for each scrape
+	var proposals []abci.GovProposal
+	if !m.probe {
+		// update the scheduled upgrade plan
+		err = m.updateUpgradePlan(client, nodeInfo)
 		if err != nil {
 			return err
 		}
+
+		// get the governance proposals in voting period
+		proposals, err = abci.GetVotingProposals(m.querier, m.versions.sdkBefore(0, 46))
+		if err != nil {
+			m.logger.Println(fmt.Sprintf("Cannot get the governance proposals (%s)", err.Error()))
+			proposals = nil
+		}
+		m.metrics.UpdateGovProposals(proposals)
 	}
 
-	// calculate the validator "valcons" address
-	valConsAddr, err := bech322.ConvertAndEncode(bech32Prefix+"valcons", consValidator.Address.Bytes())
+	// resolve the wanted validators
+	var validators []*monitoredValidator
+	var abciValidators *[]stakingTypes.Validator
+	if m.provider != nil {
+		// ICS consumer chain, the validators are resolved from the provider ones
+		validators, err = m.resolveConsumerValidators(consValidators, bech32Prefix)
+	} else {
+		// get the Validator info from the ABCI Queries
+		// NOTE: ICS Consumer chains may not have this endpoints
+		abciValidators, err = abci.GetValidators(m.querier)
+		if err != nil {
+			m.logger.Println("Cannot get ABCI Validator Info (ICS chain)")
+			abciValidators = nil
+		}
+		validators, err = m.resolveValidators(nodeInfo, consValidators, abciValidators, bech32Prefix)
+	}
 	if err != nil {
 		return err
 	}
+	// the blocks are fetched once for all the validators signatures
+	var blocks = newBlockCache(client)
+	for _, validator := range validators {
+		err = m.updateValidatorMetrics(blocks, validator, consValidators, slashingParams, nodeInfo.SyncInfo.LatestBlockHeight)
+		if err != nil {
+			return err
+		}
+		// the delegations are available only from the staking validator, a failing one doesn't stop the other metrics update
+		if validator.validator != nil {
+			err = m.updateSelfDelegation(validator, bech32Prefix)
+			if err != nil {
+				m.logger.Println(fmt.Sprintf("Cannot get the self delegation of '%s' (%s)", validator.moniker, err.Error()))
+			}
+		}
+		if m.probe {
+			continue
+		}
+		err = m.updateGovVotes(validator, proposals, bech32Prefix)
+		if err != nil {
+			return err
+		}
+		if validator.validator != nil {
+			err = m.updateDelegations(validator)
+			if err != nil {
+				m.logger.Println(fmt.Sprintf("Cannot get the delegations of '%s' (%s)", validator.moniker, err.Error()))
+			}
+		}
+	}
+	if !m.probe {
+		m.updateBalances(validators, bech32Prefix)
+	}
 
-	// update signing info
-	signingInfo, err := abci.GetValidatorSigningInfo(client, valConsAddr)
-	if err != nil {
-		return err
+	// update the distance from the active set cutoff, available only with the staking validators
+	if abciValidators != nil {
+		stakingParams, err := abci.GetStakingParams(m.querier)
+		if err != nil {
+			return err
+		}
+		m.updateActiveSet(validators, abciValidators, stakingParams)
 	}
-	prometheus.UpdateMissedBlocks(signingInfo.MissedBlocksCounter)
-	prometheus.UpdateTombstoned(signingInfo.Tombstoned)
 
-	// get the Validator info from the ABCI Queries
-	// NOTE: ICS Consumer chains may not have this endpoints
-	abciValidators, err := abci.GetValidators(client)
-	if err != nil {
-		println("Cannot get ABCI Validator Info (ICS chain)")
-		prometheus.UpdateValidatorInfo(true, nodeInfo.NodeInfo.Moniker, "", valConsAddr)
-		return nil
+	return nil
+}
+
+// updateValidatorMetrics updates the metrics of a single monitored validator
+func (m *Monitor) updateValidatorMetrics(blocks *blockCache, validator *monitoredValidator, consValidators *[]*ctypes.Validator, slashingParams *slashingTypes.Params, latestHeight int64) error {
+	var labels = validator.labels()
+
+	// set the Validator Consensus info, available only if in the active set
+	if validator.consValidator != nil {
+		m.metrics.UpdateVotingPower(labels, uint64(validator.consValidator.VotingPower))
+		m.metrics.UpdateRank(labels, votingPowerRank(validator.consValidator.Address, consValidators))
+		m.metrics.UpdateVotingPowerPercent(labels, 100*float64(validator.consValidator.VotingPower)/float64(calculateTotalVotingPower(consValidators)))
+		m.metrics.UpdateProposerPriority(labels, validator.consValidator.ProposerPriority)
+		m.metrics.UpdateProposalShare(labels, float64(validator.consValidator.VotingPower)/float64(calculateTotalVotingPower(consValidators)))
+
+		// track the validator signatures in the latest blocks, the probes not keeping the blocks window nor the counters
+		if !m.probe {
+			signatures, ok := m.signatures[validator.valcons]
+			if !ok {
+				signatures = NewSignatureTracker(m.metrics, m.config.UptimeWindow)
+				m.signatures[validator.valcons] = signatures
+			}
+			err := signatures.Update(blocks, labels, validator.consValidator.Address, latestHeight)
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		m.logger.Println(fmt.Sprintf("Validator '%s' is not in the active set", validator.valcons))
+		if signatures, ok := m.signatures[validator.valcons]; ok {
+			signatures.Skip(labels, latestHeight)
+		}
+		m.metrics.UpdateVotingPower(labels, 0)
+		m.metrics.UpdateProposalShare(labels, 0)
+		m.metrics.UpdateVotingPowerPercent(labels, 0)
+		m.metrics.DeleteRank(labels)
 	}
 
-	// retrieve the validator from the abci validators query from the consensus one
-	var wantedValidator = retrieveValidator(consValidator, abciValidators)
-	if wantedValidator == nil {
-		return errors.New("cannot retrieve Validator from ConsValidator")
+	// update signing info
+	signingInfo, err := abci.GetValidatorSigningInfo(m.querier, validator.valcons)
+	if err != nil {
+		return err
 	}
+	m.metrics.UpdateMissedBlocks(labels, signingInfo.MissedBlocksCounter)
+	m.metrics.UpdateTombstoned(labels, signingInfo.Tombstoned)
+	m.metrics.UpdateIndexOffset(labels, signingInfo.IndexOffset)
+	m.metrics.UpdateJailedUntil(labels, signingInfo.JailedUntil)
+	m.metrics.UpdateBlocksUntilJail(labels, calculateBlocksUntilJail(slashingParams, signingInfo.MissedBlocksCounter))
 
 	// validator generic info
-	prometheus.UpdateValidatorInfo(true, wantedValidator.GetMoniker(), wantedValidator.OperatorAddress, valConsAddr)
+	m.metrics.UpdateValidatorInfo(true, validator.moniker, validator.valoper, validator.valcons)
+
+	// the staking validator is not available on ICS chains
+	if validator.validator == nil {
+		return nil
+	}
+	var wantedValidator = validator.validator
 
 	// validator details
-	prometheus.UpdateCommissionMaxChangeRate(wantedValidator.Commission.MaxChangeRate.MustFloat64())
-	prometheus.UpdateCommissionMaxRate(wantedValidator.Commission.MaxRate.MustFloat64())
-	prometheus.UpdateCommissionRate(wantedValidator.Commission.Rate.MustFloat64())
-	prometheus.UpdateDelegatedTokens(wantedValidator.Tokens.Uint64())
-	prometheus.UpdateJailed(wantedValidator.Jailed)
-	prometheus.UpdateUnbondingHeight(wantedValidator.UnbondingHeight)
+	m.metrics.UpdateCommissionMaxChangeRate(labels, wantedValidator.Commission.MaxChangeRate.MustFloat64())
+	m.metrics.UpdateCommissionMaxRate(labels, wantedValidator.Commission.MaxRate.MustFloat64())
+	m.metrics.UpdateCommissionRate(labels, wantedValidator.Commission.Rate.MustFloat64())
+	m.metrics.UpdateDelegatedTokens(labels, wantedValidator.Tokens)
+	m.metrics.UpdateJailed(labels, wantedValidator.Jailed)
+	m.metrics.UpdateUnbondingHeight(labels, wantedValidator.UnbondingHeight)
 
 	// validator signing info
-	prometheus.UpdateMinSelfDelegation(wantedValidator.MinSelfDelegation.Uint64())
+	m.metrics.UpdateMinSelfDelegation(labels, wantedValidator.MinSelfDelegation)
 
 	// get the validator commission
-	commission, err := abci.GetValidatorCommission(client, wantedValidator.OperatorAddress)
+	commission, err := abci.GetValidatorCommission(m.querier, wantedValidator.OperatorAddress)
 	if err != nil {
 		return err
 	}
-	prometheus.UpdateValidatorCommission(commission)
+	m.metrics.UpdateValidatorCommission(labels, commission)
 
 	// get the validator rewards
-	rewards, err := abci.GetValidatorRewards(client, wantedValidator.OperatorAddress)
+	rewards, err := abci.GetValidatorRewards(m.querier, wantedValidator.OperatorAddress)
 	if err != nil {
 		return err
 	}
-	prometheus.UpdateValidatorRewards(rewards)
+	m.metrics.UpdateValidatorRewards(labels, rewards)
 
 	return nil
 }
 
+// calculateBlocksUntilJail calculates the further missed blocks allowed before being jailed, following the slashing module rules
+func calculateBlocksUntilJail(params *slashingTypes.Params, missedBlocks int64) int64 {
+	var minSignedBlocks = params.MinSignedPerWindow.MulInt64(params.SignedBlocksWindow).RoundInt64()
+	var maxMissedBlocks = params.SignedBlocksWindow - minSignedBlocks
+	if missedBlocks >= maxMissedBlocks {
+		return 0
+	}
+	return maxMissedBlocks - missedBlocks
+}
+
 func calculateTotalVotingPower(consValidators *[]*ctypes.Validator) int64 {
 	var totalVotingPower int64 = 0
 	for _, val := range *consValidators {
@@ -153,29 +478,16 @@ func calculateTotalVotingPower(consValidators *[]*ctypes.Validator) int64 {
 	}
 	return totalVotingPower
 }
-func getConsValidatorFromAddress(address string, consValidators *[]*ctypes.Validator) (*ctypes.Validator, int) {
-	var validator *ctypes.Validator = nil
-
-	for i, v := range *consValidators {
+func getConsValidatorFromAddress(address string, consValidators *[]*ctypes.Validator) *ctypes.Validator {
+	for _, v := range *consValidators {
 		if v.Address.String() == address {
-			return v, i + 1
+			return v
 		}
 	}
-	return validator, -100
+	return nil
 }
 
-// retrieveValidator retrieves the current validator inside the Validators from the ConsValidator
-func retrieveValidator(consValidator *ctypes.Validator, validators *[]stakingTypes.Validator) *stakingTypes.Validator {
-	for _, validator := range *validators {
-		var keyBytes = validator.ConsensusPubkey.Value
-		if len(keyBytes) > 32 {
-			keyBytes = keyBytes[2:]
-		}
-
-		vsPubKey := consValidator.PubKey.Bytes()
-		if string(keyBytes) == string(vsPubKey) {
-			return &validator
-		}
-	}
-	return nil
+// retrieveValidator retrieves the current validator inside the Validators from the ConsValidator, matching their consensus addresses
+func retrieveValidator(consValidator *ctypes.Validator, consValidators *[]*ctypes.Validator, validators *[]stakingTypes.Validator) *stakingTypes.Validator {
+	return findValidatorByConsAddress(consValidator.Address, consValidators, validators)
 }
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"github.com/cosmos/cosmos-sdk/types"
+	slashingTypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	"simple-exporter/abci"
+	"simple-exporter/config"
+	"testing"
+)
+
+func TestOrderTransports(t *testing.T) {
+	var rpcClient, grpcClient, restClient = abci.NewRESTClient("rpc"), abci.NewRESTClient("grpc"), abci.NewRESTClient("rest")
+	var all = map[string]abci.Client{config.TransportRPC: rpcClient, config.TransportGRPC: grpcClient, config.TransportREST: restClient}
+
+	var tests = []struct {
+		preferred string
+		clients   map[string]abci.Client
+		want      []abci.Client
+	}{
+		{"", all, []abci.Client{rpcClient, grpcClient, restClient}},
+		{config.TransportRPC, all, []abci.Client{rpcClient, grpcClient, restClient}},
+		{config.TransportGRPC, all, []abci.Client{grpcClient, rpcClient, restClient}},
+		{config.TransportREST, all, []abci.Client{restClient, rpcClient, grpcClient}},
+		{config.TransportREST, map[string]abci.Client{config.TransportRPC: rpcClient, config.TransportREST: restClient}, []abci.Client{restClient, rpcClient}},
+	}
+	for _, test := range tests {
+		var got = orderTransports(test.preferred, test.clients)
+		if len(got) != len(test.want) {
+			t.Errorf("orderTransports(%s) got %d clients, want %d", test.preferred, len(got), len(test.want))
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("orderTransports(%s) got a different client at position %d", test.preferred, i)
+			}
+		}
+	}
+}
+
+func TestCalculateBlocksUntilJail(t *testing.T) {
+	var tests = []struct {
+		name         string
+		window       int64
+		minSigned    string
+		missedBlocks int64
+		want         int64
+	}{
+		{"no missed blocks", 10000, "0.05", 0, 9500},
+		{"some missed blocks", 10000, "0.05", 100, 9400},
+		{"last allowed missed block", 10000, "0.05", 9499, 1},
+		{"max missed blocks", 10000, "0.05", 9500, 0},
+		{"beyond the max missed blocks", 10000, "0.05", 9600, 0},
+		{"min signed blocks rounded up", 100, "0.555", 0, 44},
+		{"min signed blocks rounded half to even", 5, "0.5", 0, 3},
+		{"all the blocks to sign", 100, "1", 0, 0},
+	}
+	for _, test := range tests {
+		var params = &slashingTypes.Params{SignedBlocksWindow: test.window, MinSignedPerWindow: types.MustNewDecFromStr(test.minSigned)}
+		if got := calculateBlocksUntilJail(params, test.missedBlocks); got != test.want {
+			t.Errorf("%s: got %d blocks until jail, want %d", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	upgradeTypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// upgradeInfo is the data/upgrade-info.json file, written by the node when halting for an upgrade
+type upgradeInfo struct {
+	Name   string `json:"name"`
+	Height int64  `json:"height"`
+}
+
+// updateCosmovisor checks the cosmovisor folders of the node home, updating the upgrade readiness metrics
+func (m *Monitor) updateCosmovisor(plan *upgradeTypes.Plan) {
+	var root = filepath.Join(m.target.DaemonHome, "cosmovisor")
+
+	// the upgrade currently run by cosmovisor
+	current, err := os.Readlink(filepath.Join(root, "current"))
+	if err != nil {
+		m.logger.Println(fmt.Sprintf("Cannot read the cosmovisor current upgrade (%s)", err.Error()))
+		m.metrics.DeleteCosmovisorCurrent()
+	} else {
+		m.metrics.UpdateCosmovisorCurrent(cosmovisorUpgradeName(current))
+	}
+
+	// the upgrade the node halted for, if any
+	info, err := readUpgradeInfo(filepath.Join(m.target.DaemonHome, "data", "upgrade-info.json"))
+	if err != nil {
+		m.logger.Println(fmt.Sprintf("Cannot read the upgrade info (%s)", err.Error()))
+	}
+	if info == nil {
+		m.metrics.DeleteUpgradeInfo()
+	} else {
+		m.metrics.UpdateUpgradeInfo(info.Name, info.Height)
+	}
+
+	// the binary of the scheduled upgrade
+	if plan == nil {
+		m.metrics.DeleteUpgradeBinaryReady()
+		return
+	}
+	var ready = isUpgradeBinaryReady(root, plan.Name, m.target.DaemonName)
+	if !ready {
+		m.logger.Println(fmt.Sprintf("Binary of the upgrade '%s' is not ready", plan.Name))
+	}
+	m.metrics.UpdateUpgradeBinaryReady(plan.Name, ready)
+}
+
+// cosmovisorUpgradeName returns the upgrade name from the "current" symlink target
+func cosmovisorUpgradeName(current string) string {
+	var name = filepath.Base(filepath.Clean(current))
+	unescaped, err := url.PathUnescape(name)
+	if err != nil {
+		return name
+	}
+	return unescaped
+}
+
+// isUpgradeBinaryReady checks if the upgrade binary is staged inside the cosmovisor upgrades folder and executable
+func isUpgradeBinaryReady(root string, name string, daemonName string) bool {
+	// cosmovisor escapes the upgrade name, older versions also lower case it
+	for _, dirName := range []string{url.PathEscape(name), url.PathEscape(strings.ToLower(name))} {
+		var binDir = filepath.Join(root, "upgrades", dirName, "bin")
+		if daemonName != "" {
+			if isExecutable(filepath.Join(binDir, daemonName)) {
+				return true
+			}
+			continue
+		}
+
+		entries, err := os.ReadDir(binDir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if isExecutable(filepath.Join(binDir, entry.Name())) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isExecutable checks if the path is a regular executable file, following the symlinks
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0
+}
+
+// readUpgradeInfo reads the upgrade info file, nil if it doesn't exist
+func readUpgradeInfo(path string) (*upgradeInfo, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var info upgradeInfo
+	err = json.Unmarshal(data, &info)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
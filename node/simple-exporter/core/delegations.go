@@ -0,0 +1,120 @@
+package core
+
+import (
+	"fmt"
+	"github.com/cosmos/cosmos-sdk/types"
+	stakingTypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"simple-exporter/abci"
+	"simple-exporter/prometheus"
+	"sort"
+	"time"
+)
+
+// topDelegationsCount is the number of the largest delegations exported
+const topDelegationsCount = 5
+
+// delegationsTracker tracks the validator delegations across the updates, detecting the large changes
+type delegationsTracker struct {
+	lastUpdate  time.Time
+	delegations map[string]types.Int // delegations tokens by delegator address, nil before the first update
+}
+
+// updateDelegations updates the validator delegations metrics, at most once every delegations refresh interval
+func (m *Monitor) updateDelegations(validator *monitoredValidator) error {
+	tracker, ok := m.delegations[validator.valcons]
+	if !ok {
+		tracker = &delegationsTracker{}
+		m.delegations[validator.valcons] = tracker
+	}
+	if time.Since(tracker.lastUpdate) < m.config.DelegationsRefresh.Duration() {
+		return nil
+	}
+
+	var labels = validator.labels()
+	var operatorAddress = validator.validator.OperatorAddress
+	delegations, err := abci.GetValidatorDelegations(m.querier, operatorAddress)
+	if err != nil {
+		return err
+	}
+	unbondings, err := abci.GetValidatorUnbondingDelegations(m.querier, operatorAddress)
+	if err != nil {
+		return err
+	}
+
+	var current = make(map[string]types.Int)
+	for _, delegation := range delegations {
+		current[delegation.Delegation.DelegatorAddress] = delegation.Balance.Amount
+	}
+	var unbondingTokens = types.ZeroInt()
+	for _, unbonding := range unbondings {
+		for _, entry := range unbonding.Entries {
+			unbondingTokens = unbondingTokens.Add(entry.Balance)
+		}
+	}
+
+	m.metrics.UpdateDelegations(labels, len(delegations), unbondingTokens)
+	m.metrics.UpdateTopDelegations(labels, topDelegations(delegations, topDelegationsCount))
+
+	if tracker.delegations != nil && m.target.LargeDelegationThreshold > 0 {
+		m.trackLargeDelegationChanges(labels, tracker.delegations, current)
+	}
+	tracker.delegations = current
+	tracker.lastUpdate = time.Now()
+	return nil
+}
+
+// trackLargeDelegationChanges counts the delegations changed more than the threshold since the previous update
+func (m *Monitor) trackLargeDelegationChanges(labels prometheus.Validator, previous map[string]types.Int, current map[string]types.Int) {
+	var changes = make(map[string]types.Int)
+	for delegator, tokens := range current {
+		var previousTokens, ok = previous[delegator]
+		if !ok {
+			previousTokens = types.ZeroInt()
+		}
+		changes[delegator] = tokens.Sub(previousTokens)
+	}
+	// the removed delegations are full undelegations or redelegations
+	for delegator, tokens := range previous {
+		if _, ok := current[delegator]; !ok {
+			changes[delegator] = tokens.Neg()
+		}
+	}
+
+	for delegator, change := range changes {
+		var changeTokens = types.NewDecFromInt(change).MustFloat64()
+		if changeTokens >= m.target.LargeDelegationThreshold {
+			m.logger.Println(fmt.Sprintf("Large delegation of %s tokens from '%s' to '%s'", change, delegator, labels.Moniker))
+			m.metrics.IncLargeDelegationChange(labels, true)
+		} else if -changeTokens >= m.target.LargeDelegationThreshold {
+			m.logger.Println(fmt.Sprintf("Large undelegation of %s tokens from '%s' to '%s'", change.Neg(), delegator, labels.Moniker))
+			m.metrics.IncLargeDelegationChange(labels, false)
+		}
+	}
+}
+
+// topDelegations returns the largest delegations
+func topDelegations(delegations stakingTypes.DelegationResponses, count int) stakingTypes.DelegationResponses {
+	var sorted = append(stakingTypes.DelegationResponses{}, delegations...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Balance.Amount.GT(sorted[j].Balance.Amount)
+	})
+	if len(sorted) > count {
+		sorted = sorted[:count]
+	}
+	return sorted
+}
+
+// updateSelfDelegation updates the tokens delegated by the validator operator account and their margin over the min self delegation.
+// The validator is jailed as soon as the self delegation goes below the min self delegation
+func (m *Monitor) updateSelfDelegation(validator *monitoredValidator, bech32Prefix string) error {
+	operatorAccount, err := operatorAccountAddress(validator.validator.OperatorAddress, bech32Prefix)
+	if err != nil {
+		return err
+	}
+	selfDelegation, err := abci.GetDelegation(m.querier, operatorAccount, validator.validator.OperatorAddress)
+	if err != nil {
+		return err
+	}
+	m.metrics.UpdateSelfDelegation(validator.labels(), selfDelegation, selfDelegation.Sub(validator.validator.MinSelfDelegation))
+	return nil
+}
@@ -0,0 +1,171 @@
+package core
+
+import (
+	"context"
+	"errors"
+	sdkTypes "github.com/cosmos/cosmos-sdk/types"
+	stakingTypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"simple-exporter/prometheus"
+	"simple-exporter/types"
+	"testing"
+)
+
+// counterValue returns the value of the counter with the given labels, false if it is not exported
+func counterValue(t *testing.T, registry *prom.Registry, name string, labels map[string]string) (float64, bool) {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			var matched = 0
+			for _, label := range metric.GetLabel() {
+				if value, ok := labels[label.GetName()]; ok && value == label.GetValue() {
+					matched++
+				}
+			}
+			if matched == len(labels) {
+				return metric.GetCounter().GetValue(), true
+			}
+		}
+	}
+	return 0, false
+}
+
+func TestTrackLargeDelegationChanges(t *testing.T) {
+	var tokens = func(delegations map[string]int64) map[string]sdkTypes.Int {
+		var converted = make(map[string]sdkTypes.Int)
+		for delegator, amount := range delegations {
+			converted[delegator] = sdkTypes.NewInt(amount)
+		}
+		return converted
+	}
+
+	var tests = []struct {
+		name             string
+		previous         map[string]int64
+		current          map[string]int64
+		wantDelegation   float64
+		wantUndelegation float64
+	}{
+		{"new delegation above the threshold", map[string]int64{}, map[string]int64{"a": 1500}, 1, 0},
+		{"new delegation at the threshold", map[string]int64{}, map[string]int64{"a": 1000}, 1, 0},
+		{"new delegation below the threshold", map[string]int64{}, map[string]int64{"a": 999}, 0, 0},
+		{"removed delegation above the threshold", map[string]int64{"a": 2000}, map[string]int64{}, 0, 1},
+		{"removed delegation at the threshold", map[string]int64{"a": 1000}, map[string]int64{}, 0, 1},
+		{"removed delegation below the threshold", map[string]int64{"a": 500}, map[string]int64{}, 0, 0},
+		{"partial delegation above the threshold", map[string]int64{"a": 1000}, map[string]int64{"a": 2500}, 1, 0},
+		{"partial delegation below the threshold", map[string]int64{"a": 1000}, map[string]int64{"a": 1999}, 0, 0},
+		{"partial undelegation above the threshold", map[string]int64{"a": 3000}, map[string]int64{"a": 1000}, 0, 1},
+		{"partial undelegation below the threshold", map[string]int64{"a": 3000}, map[string]int64{"a": 2500}, 0, 0},
+		{"unchanged delegation", map[string]int64{"a": 3000}, map[string]int64{"a": 3000}, 0, 0},
+		{"several delegators", map[string]int64{"a": 3000, "b": 5000, "c": 100}, map[string]int64{"a": 5000, "c": 100, "d": 1000}, 2, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var monitor = newTestMonitor(nil)
+			monitor.target.LargeDelegationThreshold = 1000
+			var registry = prom.NewRegistry()
+			monitor.collectors.Register(registry)
+
+			monitor.trackLargeDelegationChanges(prometheus.Validator{Moniker: "validator"}, tokens(test.previous), tokens(test.current))
+			for direction, want := range map[string]float64{"delegation": test.wantDelegation, "undelegation": test.wantUndelegation} {
+				value, _ := counterValue(t, registry, "validator_large_delegation_changes_total", map[string]string{"moniker": "validator", "direction": direction})
+				if value != want {
+					t.Errorf("got %f large %s changes, want %f", value, direction, want)
+				}
+			}
+		})
+	}
+}
+
+// delegationClient answers the delegation queries with the given delegation, or its application error log
+type delegationClient struct {
+	tokens int64
+	log    string
+	err    error
+}
+
+func (c delegationClient) Query(_ context.Context, _ string, _ []byte) (*types.ResultABCIQuery, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.log != "" {
+		return &types.ResultABCIQuery{Response: types.ResponseQuery{Code: 5, Log: c.log}}, nil
+	}
+	var response = stakingTypes.QueryDelegationResponse{DelegationResponse: &stakingTypes.DelegationResponse{Balance: sdkTypes.NewInt64Coin("uatom", c.tokens)}}
+	value, err := response.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return &types.ResultABCIQuery{Response: types.ResponseQuery{Value: value}}, nil
+}
+
+func TestUpdateSelfDelegation(t *testing.T) {
+	var valoper = "cosmosvaloper1ntepzv5m9lyzuhh7jpsx93espq5pnv3lld2rnk"
+
+	var tests = []struct {
+		name       string
+		client     delegationClient
+		wantTokens float64
+		wantMargin float64
+		wantErr    bool
+	}{
+		{
+			name:       "self delegation above the min self delegation",
+			client:     delegationClient{tokens: 1500},
+			wantTokens: 1500,
+			wantMargin: 500,
+		},
+		{
+			name:       "fully undelegated operator",
+			client:     delegationClient{log: "rpc error: code = NotFound desc = delegation with delegator cosmos1ntepzv5m9lyzuhh7jpsx93espq5pnv3lwwzrfr not found for validator " + valoper + ": key not found"},
+			wantTokens: 0,
+			wantMargin: -1000,
+		},
+		{
+			name:    "other application error",
+			client:  delegationClient{log: "rpc error: code = InvalidArgument desc = invalid validator address"},
+			wantErr: true,
+		},
+		{
+			name:    "endpoint error",
+			client:  delegationClient{err: errors.New("connection refused")},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var monitor = newTestMonitor(test.client)
+			var registry = prom.NewRegistry()
+			monitor.collectors.Register(registry)
+
+			var validator = &monitoredValidator{
+				moniker:   "validator",
+				valcons:   "cosmosvalcons1test",
+				validator: &stakingTypes.Validator{OperatorAddress: valoper, MinSelfDelegation: sdkTypes.NewInt(1000)},
+			}
+			err := monitor.updateSelfDelegation(validator, "cosmos")
+			if test.wantErr {
+				if err == nil {
+					t.Error("got no error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var labels = map[string]string{"moniker": "validator"}
+			tokens, _ := gaugeValue(t, registry, "validator_self_delegation_tokens", labels)
+			margin, _ := gaugeValue(t, registry, "validator_self_delegation_margin_tokens", labels)
+			if tokens != test.wantTokens || margin != test.wantMargin {
+				t.Errorf("got self delegation %f and margin %f, want %f and %f", tokens, margin, test.wantTokens, test.wantMargin)
+			}
+		})
+	}
+}
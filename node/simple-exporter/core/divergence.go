@@ -0,0 +1,144 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	tmhttp "github.com/tendermint/tendermint/rpc/client/http"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	"simple-exporter/rpc"
+)
+
+// divergence kinds
+const (
+	divergenceBlockHash = "block_hash" // different blocks at the same height, the nodes forked
+	divergenceAppHash   = "app_hash"   // different application states, ex. non-determinism after a bad upgrade
+)
+
+// divergenceTracker holds the comparison state of the monitored node with a reference node
+type divergenceTracker struct {
+	agreedHeight int64  // latest height with the same block on both nodes, 0 if not compared yet
+	height       int64  // first divergent height, 0 if the nodes agree
+	kind         string // divergence kind, empty if the nodes agree
+}
+
+// updateDivergences compares the node blocks with the reference nodes ones at their shared heights
+func (m *Monitor) updateDivergences(client *tmhttp.HTTP, nodeInfo *coretypes.ResultStatus, references []referenceStatus) {
+	for _, reference := range references {
+		tracker, ok := m.divergences[reference.endpoint]
+		if !ok {
+			tracker = &divergenceTracker{}
+			m.divergences[reference.endpoint] = tracker
+		}
+		err := tracker.update(client, nodeInfo, reference)
+		if err != nil {
+			m.logger.Println(fmt.Sprintf("Cannot compare the blocks with the reference '%s' (%s)", reference.endpoint, err.Error()))
+			continue
+		}
+
+		if tracker.height == 0 {
+			m.metrics.DeleteDivergence(reference.endpoint)
+			continue
+		}
+		m.logger.Println(fmt.Sprintf("Node diverged from the reference '%s' at height %d (%s)", reference.endpoint, tracker.height, tracker.kind))
+		m.metrics.UpdateDivergence(reference.endpoint, tracker.kind, tracker.height)
+	}
+}
+
+// update compares the node and the reference blocks at the highest shared height.
+// The node state is also compared with the next reference block, when the node is behind (ex. halted on a wrong AppHash)
+func (t *divergenceTracker) update(client *tmhttp.HTTP, nodeInfo *coretypes.ResultStatus, reference referenceStatus) error {
+	var height = min(nodeInfo.SyncInfo.LatestBlockHeight, reference.syncInfo.LatestBlockHeight)
+	if height <= 0 {
+		return nil
+	}
+	nodeHeader, err := rpc.GetBlockHeader(client, height)
+	if err != nil {
+		return err
+	}
+	referenceHeader, err := rpc.GetBlockHeader(reference.client, height)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(nodeHeader.Hash(), referenceHeader.Hash()) {
+		// the divergence is already tracked, the next blocks differ too
+		if t.height != 0 {
+			return nil
+		}
+		return t.trackDivergence(client, reference.client, height)
+	}
+	t.agreedHeight = height
+	t.height = 0
+	t.kind = ""
+
+	// the header AppHash is the state before the block, the node state after its latest block is only in /abci_info
+	// and is compared with the AppHash of the next reference block
+	if height == nodeInfo.SyncInfo.LatestBlockHeight && reference.syncInfo.LatestBlockHeight > height {
+		abciInfo, err := rpc.GetABCIInfo(client)
+		if err != nil {
+			return err
+		}
+		// the node committed a new block meanwhile, compared on the next update
+		if abciInfo.Response.LastBlockHeight != height {
+			return nil
+		}
+		nextHeader, err := rpc.GetBlockHeader(reference.client, height+1)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(nextHeader.AppHash, abciInfo.Response.LastBlockAppHash) {
+			t.height = height + 1
+			t.kind = divergenceAppHash
+		}
+	}
+	return nil
+}
+
+// trackDivergence searches the first divergent height after the latest agreed one, up to the given divergent height
+func (t *divergenceTracker) trackDivergence(client *tmhttp.HTTP, referenceClient *tmhttp.HTTP, divergentHeight int64) error {
+	// binary search of the first divergent height, unknown if the nodes were never compared
+	if t.agreedHeight > 0 {
+		var low = t.agreedHeight
+		for divergentHeight-low > 1 {
+			var middle = low + (divergentHeight-low)/2
+			same, err := sameBlock(client, referenceClient, middle)
+			if err != nil {
+				return err
+			}
+			if same {
+				low = middle
+			} else {
+				divergentHeight = middle
+			}
+		}
+	}
+
+	// the AppHash of the first divergent block tells a state divergence from a fork
+	nodeHeader, err := rpc.GetBlockHeader(client, divergentHeight)
+	if err != nil {
+		return err
+	}
+	referenceHeader, err := rpc.GetBlockHeader(referenceClient, divergentHeight)
+	if err != nil {
+		return err
+	}
+	t.height = divergentHeight
+	t.kind = divergenceBlockHash
+	if !bytes.Equal(nodeHeader.AppHash, referenceHeader.AppHash) {
+		t.kind = divergenceAppHash
+	}
+	return nil
+}
+
+// sameBlock checks if the two nodes have the same block at the given height
+func sameBlock(client *tmhttp.HTTP, referenceClient *tmhttp.HTTP, height int64) (bool, error) {
+	nodeHeader, err := rpc.GetBlockHeader(client, height)
+	if err != nil {
+		return false, err
+	}
+	referenceHeader, err := rpc.GetBlockHeader(referenceClient, height)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(nodeHeader.Hash(), referenceHeader.Hash()), nil
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	abcitypes "github.com/tendermint/tendermint/abci/types"
+	tmhttp "github.com/tendermint/tendermint/rpc/client/http"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	ctypes "github.com/tendermint/tendermint/types"
+	"simple-exporter/testutil"
+	"testing"
+)
+
+// fakeChain is the chain of a fake node, the state after each block being identified by its height
+type fakeChain struct {
+	latest   int64 // latest committed height
+	forkAt   int64 // first height with a different block than the other nodes, 0 if none
+	badState int64 // first height leading to a different state than the other nodes, 0 if none
+}
+
+// appHash returns the state after the block at the given height
+func (c fakeChain) appHash(height int64) []byte {
+	if c.badState > 0 && height >= c.badState {
+		return []byte(fmt.Sprintf("bad-state-%d", height))
+	}
+	return []byte(fmt.Sprintf("state-%d", height))
+}
+
+// header returns the block header at the given height, holding the state before the block
+func (c fakeChain) header(height int64) ctypes.Header {
+	var header = ctypes.Header{ChainID: "test-1", Height: height, AppHash: c.appHash(height - 1), ValidatorsHash: []byte("validators")}
+	if c.forkAt > 0 && height >= c.forkAt {
+		header.ProposerAddress = []byte("fork")
+	}
+	return header
+}
+
+// status returns the node /status, the latest AppHash being the one of the latest header like on the real nodes
+func (c fakeChain) status() *coretypes.ResultStatus {
+	return &coretypes.ResultStatus{SyncInfo: coretypes.SyncInfo{LatestBlockHeight: c.latest, LatestAppHash: c.header(c.latest).AppHash}}
+}
+
+func (c fakeChain) client(t *testing.T) *tmhttp.HTTP {
+	return testutil.NewRPC(t, map[string]testutil.Method{
+		"blockchain": func(params map[string]json.RawMessage) (interface{}, error) {
+			var height = testutil.Int64Param(params, "minHeight")
+			if height < 1 || height > c.latest {
+				return nil, errors.New(fmt.Sprintf("height %d is not available", height))
+			}
+			return &coretypes.ResultBlockchainInfo{LastHeight: c.latest, BlockMetas: []*ctypes.BlockMeta{{Header: c.header(height)}}}, nil
+		},
+		"abci_info": func(params map[string]json.RawMessage) (interface{}, error) {
+			return &coretypes.ResultABCIInfo{Response: abcitypes.ResponseInfo{LastBlockHeight: c.latest, LastBlockAppHash: c.appHash(c.latest)}}, nil
+		},
+	})
+}
+
+func TestDivergenceTrackerUpdate(t *testing.T) {
+	var tests = []struct {
+		name         string
+		node         fakeChain
+		reference    fakeChain
+		agreedHeight int64
+		height       int64
+		kind         string
+	}{
+		{"same latest block", fakeChain{latest: 100}, fakeChain{latest: 100}, 0, 0, ""},
+		{"node one block behind", fakeChain{latest: 99}, fakeChain{latest: 100}, 0, 0, ""},
+		{"node far behind", fakeChain{latest: 50}, fakeChain{latest: 100}, 0, 0, ""},
+		{"node ahead", fakeChain{latest: 101}, fakeChain{latest: 100}, 0, 0, ""},
+		{"node halted on a wrong state", fakeChain{latest: 100, badState: 100}, fakeChain{latest: 120}, 90, 101, divergenceAppHash},
+		{"fork", fakeChain{latest: 100, forkAt: 57}, fakeChain{latest: 120}, 10, 57, divergenceBlockHash},
+		{"state divergence", fakeChain{latest: 100, badState: 32}, fakeChain{latest: 100}, 10, 33, divergenceAppHash},
+		{"fork before the first comparison", fakeChain{latest: 100, forkAt: 57}, fakeChain{latest: 100}, 0, 100, divergenceBlockHash},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var tracker = &divergenceTracker{agreedHeight: test.agreedHeight}
+			var reference = referenceStatus{endpoint: "reference", client: test.reference.client(t), syncInfo: test.reference.status().SyncInfo}
+			err := tracker.update(test.node.client(t), test.node.status(), reference)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tracker.height != test.height || tracker.kind != test.kind {
+				t.Errorf("got divergence at %d (%s), want %d (%s)", tracker.height, tracker.kind, test.height, test.kind)
+			}
+		})
+	}
+}
+
+func TestDivergenceTrackerRecovery(t *testing.T) {
+	var reference = fakeChain{latest: 100}
+	var referenceStatus = referenceStatus{endpoint: "reference", client: reference.client(t), syncInfo: reference.status().SyncInfo}
+	var tracker = &divergenceTracker{}
+
+	err := tracker.update(fakeChain{latest: 60}.client(t), fakeChain{latest: 60}.status(), referenceStatus)
+	if err != nil || tracker.height != 0 || tracker.agreedHeight != 60 {
+		t.Fatalf("got divergence at %d, agreed height %d (%v), want none at 60", tracker.height, tracker.agreedHeight, err)
+	}
+
+	// the first divergent height is kept while the node goes on with its fork
+	for _, latest := range []int64{80, 90} {
+		var node = fakeChain{latest: latest, forkAt: 70}
+		err = tracker.update(node.client(t), node.status(), referenceStatus)
+		if err != nil || tracker.height != 70 || tracker.kind != divergenceBlockHash {
+			t.Fatalf("got divergence at %d (%s, %v), want 70 (%s)", tracker.height, tracker.kind, err, divergenceBlockHash)
+		}
+	}
+
+	// the node synced again from the other nodes
+	var node = fakeChain{latest: 95}
+	err = tracker.update(node.client(t), node.status(), referenceStatus)
+	if err != nil || tracker.height != 0 || tracker.kind != "" {
+		t.Fatalf("got divergence at %d (%s, %v), want none", tracker.height, tracker.kind, err)
+	}
+}
@@ -0,0 +1,138 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	jsonrpcclient "github.com/tendermint/tendermint/rpc/jsonrpc/client"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	ctypes "github.com/tendermint/tendermint/types"
+	"time"
+)
+
+// blockSubscription holds the websocket client receiving the new blocks events
+type blockSubscription struct {
+	client *jsonrpcclient.WSClient
+}
+
+// rawBlockEvent holds only the height of the NewBlock and NewBlockHeader events.
+// The events are decoded from the raw JSON, instead of the typed events of the Tendermint WSEvents client, because their results changed across the Tendermint/CometBFT versions
+// (base64 attributes in v0.34, plain ones in v0.37, finalize_block results in v0.38)
+type rawBlockEvent struct {
+	Data struct {
+		Value struct {
+			Block *struct {
+				Header rawHeader `json:"header"`
+			} `json:"block"`
+			Header *rawHeader `json:"header"`
+		} `json:"value"`
+	} `json:"data"`
+}
+
+type rawHeader struct {
+	Height int64 `json:"height,string"`
+}
+
+// subscribeNewBlocks opens a websocket to the RPC endpoint and subscribes to the NewBlock events.
+// The websocket client reconnects by itself after a drop, the subscription being renewed on each reconnection
+func subscribeNewBlocks(rpcAddr string) (*blockSubscription, error) {
+	var subscription = &blockSubscription{}
+	client, err := jsonrpcclient.NewWS(rpcAddr, "/websocket", jsonrpcclient.OnReconnect(subscription.resubscribe))
+	if err != nil {
+		return nil, err
+	}
+	subscription.client = client
+	err = client.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	err = subscription.subscribe()
+	if err != nil {
+		subscription.close()
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// subscribe subscribes to the NewBlock events of the websocket
+func (s *blockSubscription) subscribe() error {
+	var ctx = context.Background()
+	bctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return s.client.Subscribe(bctx, ctypes.EventQueryNewBlock.String())
+}
+
+// resubscribe renews the subscription after a reconnection, the node dropping the subscriptions with the socket.
+// If it fails, no event is received and the websocket idle timeout falls back to polling
+func (s *blockSubscription) resubscribe() {
+	_ = s.subscribe()
+}
+
+// close unsubscribes from the events and closes the websocket
+func (s *blockSubscription) close() {
+	var ctx = context.Background()
+	bctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// the socket may be already dropped, errors are expected here
+	_ = s.client.UnsubscribeAll(bctx)
+	_ = s.client.Stop()
+}
+
+// next waits for the next block event, returning the newest height once the pending events are drained,
+// so that the blocks received during a slow refresh don't trigger one refresh each.
+// It returns false if no event is received before the timeout
+func (s *blockSubscription) next(timeout time.Duration) (int64, bool) {
+	var timer = time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var height int64 = 0
+	for height == 0 {
+		select {
+		case response, ok := <-s.client.ResponsesCh:
+			if !ok {
+				return 0, false
+			}
+			height = responseHeight(response)
+		case <-timer.C:
+			return 0, false
+		}
+	}
+	for {
+		select {
+		case response, ok := <-s.client.ResponsesCh:
+			// the received height is refreshed before noticing the closed websocket
+			if !ok {
+				return height, true
+			}
+			height = max(height, responseHeight(response))
+		default:
+			return height, true
+		}
+	}
+}
+
+// responseHeight returns the block height of a websocket response, 0 for the subscriptions replies and the errors
+func responseHeight(response rpctypes.RPCResponse) int64 {
+	if response.Error != nil {
+		return 0
+	}
+	return eventHeight(response.Result)
+}
+
+// eventHeight extracts the block height from a raw NewBlock or NewBlockHeader event, 0 if not a block event
+func eventHeight(result json.RawMessage) int64 {
+	var event rawBlockEvent
+	err := json.Unmarshal(result, &event)
+	if err != nil {
+		return 0
+	}
+	if event.Data.Value.Block != nil {
+		return event.Data.Value.Block.Header.Height
+	}
+	if event.Data.Value.Header != nil {
+		return event.Data.Value.Header.Height
+	}
+	return 0
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/websocket"
+	jsonrpcclient "github.com/tendermint/tendermint/rpc/jsonrpc/client"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"net/http"
+	"net/http/httptest"
+	"simple-exporter/rpc"
+	"simple-exporter/testutil"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEventHeight(t *testing.T) {
+	for _, chain := range testutil.Chains {
+		t.Run(chain.Name, func(t *testing.T) {
+			status, err := rpc.GetNodeInfo(testutil.NewFixtureRPC(t, chain))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, event := range []string{"NewBlock", "NewBlockHeader"} {
+				var response rpctypes.RPCResponse
+				err := json.Unmarshal(chain.Fixture(t, "event_"+event+".json"), &response)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if height := eventHeight(response.Result); height != status.SyncInfo.LatestBlockHeight {
+					t.Errorf("got %s height %d, want %d", event, height, status.SyncInfo.LatestBlockHeight)
+				}
+			}
+
+			// the subscriptions replies are not block events
+			if height := eventHeight(json.RawMessage(`{}`)); height != 0 {
+				t.Errorf("got height %d from a subscription reply", height)
+			}
+		})
+	}
+}
+
+func TestBlockSubscriptionNext(t *testing.T) {
+	var header = func(height int64) rpctypes.RPCResponse {
+		return rpctypes.RPCResponse{Result: json.RawMessage(fmt.Sprintf(`{"data":{"type":"tendermint/event/NewBlockHeader","value":{"header":{"height":"%d"}}}}`, height))}
+	}
+	var subscription = &blockSubscription{client: &jsonrpcclient.WSClient{ResponsesCh: make(chan rpctypes.RPCResponse, 10)}}
+
+	// the blocks received during a refresh are drained down to the newest one
+	subscription.client.ResponsesCh <- rpctypes.RPCResponse{Result: json.RawMessage(`{}`)}
+	subscription.client.ResponsesCh <- header(10)
+	subscription.client.ResponsesCh <- rpctypes.RPCResponse{Error: &rpctypes.RPCError{Code: -32603, Message: "Internal error"}}
+	subscription.client.ResponsesCh <- header(12)
+	subscription.client.ResponsesCh <- header(11)
+	height, ok := subscription.next(time.Second)
+	if !ok || height != 12 {
+		t.Errorf("got height %d (%t), want 12", height, ok)
+	}
+
+	// no event before the timeout
+	height, ok = subscription.next(10 * time.Millisecond)
+	if ok {
+		t.Errorf("got height %d without events", height)
+	}
+
+	// the pending height is returned before noticing the closed websocket
+	subscription.client.ResponsesCh <- header(13)
+	close(subscription.client.ResponsesCh)
+	height, ok = subscription.next(time.Second)
+	if !ok || height != 13 {
+		t.Errorf("got height %d (%t), want 13", height, ok)
+	}
+	if _, ok = subscription.next(time.Second); ok {
+		t.Error("got an event from the closed websocket")
+	}
+}
+
+// newTestWSServer serves a websocket answering the subscriptions, sending the block event of the given height
+// after each one. It drops the first connection after its event, recording the subscriptions queries
+func newTestWSServer(t *testing.T, heights []int64, queries *[]string) string {
+	t.Helper()
+	var mutex sync.Mutex
+	var upgrader = websocket.Upgrader{}
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var request rpctypes.RPCRequest
+			err = conn.ReadJSON(&request)
+			if err != nil {
+				return
+			}
+			if request.Method != "subscribe" {
+				continue
+			}
+			var params struct {
+				Query string `json:"query"`
+			}
+			_ = json.Unmarshal(request.Params, &params)
+			mutex.Lock()
+			*queries = append(*queries, params.Query)
+			var subscriptions = len(*queries)
+			mutex.Unlock()
+
+			_ = conn.WriteJSON(rpctypes.NewRPCSuccessResponse(request.ID, struct{}{}))
+			if subscriptions > len(heights) {
+				continue
+			}
+			var event = fmt.Sprintf(`{"query":"%s","data":{"type":"tendermint/event/NewBlock","value":{"block":{"header":{"height":"%d"}}}}}`, params.Query, heights[subscriptions-1])
+			_ = conn.WriteJSON(rpctypes.RPCResponse{JSONRPC: "2.0", ID: request.ID, Result: json.RawMessage(event)})
+			if subscriptions == 1 {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestBlockSubscriptionReconnect(t *testing.T) {
+	var queries []string
+	subscription, err := subscribeNewBlocks(newTestWSServer(t, []int64{10, 11}, &queries))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer subscription.close()
+
+	height, ok := subscription.next(5 * time.Second)
+	if !ok || height != 10 {
+		t.Fatalf("got height %d (%t), want 10", height, ok)
+	}
+
+	// the dropped websocket reconnects and resubscribes, receiving the next blocks
+	height, ok = subscription.next(5 * time.Second)
+	if !ok || height != 11 {
+		t.Errorf("got height %d (%t) after the reconnection, want 11", height, ok)
+	}
+	if len(queries) != 2 || queries[0] != "tm.event='NewBlock'" || queries[1] != queries[0] {
+		t.Errorf("got subscriptions %v, want the NewBlock events twice", queries)
+	}
+}
@@ -0,0 +1,32 @@
+package core
+
+import (
+	"fmt"
+	govV1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	"simple-exporter/abci"
+)
+
+// updateGovVotes updates the votes of the validator operator account on the proposals in voting period
+func (m *Monitor) updateGovVotes(validator *monitoredValidator, proposals []abci.GovProposal, bech32Prefix string) error {
+	// the operator account is available only from the staking validator
+	if validator.validator == nil {
+		return nil
+	}
+	voter, err := operatorAccountAddress(validator.validator.OperatorAddress, bech32Prefix)
+	if err != nil {
+		return err
+	}
+
+	var votes = make(map[uint64][]govV1.VoteOption)
+	for _, proposal := range proposals {
+		// a failing proposal doesn't stop the other votes update, its vote metrics being removed instead of reporting a missing vote
+		options, err := abci.GetGovVote(m.querier, proposal.ID, voter, m.versions.sdkBefore(0, 46))
+		if err != nil {
+			m.logger.Println(fmt.Sprintf("Cannot get the vote of '%s' on the proposal %d (%s)", voter, proposal.ID, err.Error()))
+			continue
+		}
+		votes[proposal.ID] = options
+	}
+	m.metrics.UpdateGovVotes(validator.labels(), votes)
+	return nil
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"context"
+	"errors"
+	govV1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	stakingTypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"simple-exporter/abci"
+	"simple-exporter/testutil"
+	"simple-exporter/types"
+	"strings"
+	"testing"
+)
+
+// failingVoteClient fails the vote queries of the given proposal, like a timing out endpoint
+type failingVoteClient struct {
+	client     abci.Client
+	proposalID uint64
+}
+
+func (c failingVoteClient) Query(ctx context.Context, path string, data []byte) (*types.ResultABCIQuery, error) {
+	if strings.HasSuffix(path, "/Vote") {
+		// the v1 and v1beta1 vote requests share the proposal id field
+		var request govV1.QueryVoteRequest
+		if request.Unmarshal(data) == nil && request.ProposalId == c.proposalID {
+			return nil, errors.New("context deadline exceeded")
+		}
+	}
+	return c.client.Query(ctx, path, data)
+}
+
+func TestUpdateGovVotesError(t *testing.T) {
+	var chain = testutil.Chains[2]
+	var monitor = newTestMonitor(failingVoteClient{client: abci.NewRPCClient(testutil.NewFixtureRPC(t, chain)), proposalID: 1})
+	var registry = prom.NewRegistry()
+	monitor.collectors.Register(registry)
+
+	var validator = &monitoredValidator{
+		moniker:   "validator",
+		valcons:   "cosmosvalcons1test",
+		validator: &stakingTypes.Validator{OperatorAddress: "cosmosvaloper1ntepzv5m9lyzuhh7jpsx93espq5pnv3lld2rnk"},
+	}
+	err := monitor.updateGovVotes(validator, []abci.GovProposal{{ID: 1}, {ID: 2}}, "cosmos")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the failing proposal doesn't stop the other votes update
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var voted []string
+	for _, family := range families {
+		if family.GetName() != "validator_gov_voted" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "proposal_id" {
+					voted = append(voted, label.GetValue())
+				}
+			}
+		}
+	}
+	if strings.Join(voted, ",") != "2" {
+		t.Errorf("got the votes of the proposals [%s], want [2]", strings.Join(voted, ","))
+	}
+}
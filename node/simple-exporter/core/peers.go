@@ -0,0 +1,45 @@
+package core
+
+import (
+	"fmt"
+	tmhttp "github.com/tendermint/tendermint/rpc/client/http"
+	"simple-exporter/rpc"
+	"strings"
+)
+
+// updatePeers updates the node P2P peers metrics, and the connection status of the configured peers
+func (m *Monitor) updatePeers(client *tmhttp.HTTP) {
+	// the public RPC endpoints often disable the /net_info route
+	netInfo, err := rpc.GetNetInfo(client)
+	if err != nil {
+		m.logger.Println(fmt.Sprintf("Cannot get the node peers (%s)", err.Error()))
+		m.metrics.DeletePeers()
+		return
+	}
+
+	var inbound, outbound int
+	var connected = make(map[string]bool)
+	for _, peer := range netInfo.Peers {
+		if peer.IsOutbound {
+			outbound++
+		} else {
+			inbound++
+		}
+		connected[strings.ToLower(string(peer.NodeInfo.DefaultNodeID))] = true
+	}
+	m.metrics.UpdatePeers(inbound, outbound)
+	for _, peer := range netInfo.Peers {
+		var status = peer.ConnectionStatus
+		m.metrics.UpdatePeer(strings.ToLower(string(peer.NodeInfo.DefaultNodeID)), peer.NodeInfo.Moniker, peer.RemoteIP, peer.IsOutbound, status.SendMonitor.CurRate, status.RecvMonitor.CurRate, status.Duration)
+	}
+
+	// the persistent peers are configured as id@host:port
+	for _, peer := range m.target.Peers {
+		id, _, _ := strings.Cut(peer, "@")
+		id = strings.ToLower(id)
+		if !connected[id] {
+			m.logger.Println(fmt.Sprintf("Peer '%s' is not connected", id))
+		}
+		m.metrics.UpdatePeerConnected(id, connected[id])
+	}
+}
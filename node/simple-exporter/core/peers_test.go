@@ -0,0 +1,85 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/tendermint/tendermint/p2p"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	"simple-exporter/testutil"
+	"testing"
+)
+
+func TestUpdatePeers(t *testing.T) {
+	var sentry = "2f3d1a0c8e5b4a7d9c6e1f0a3b5d7c9e1f2a4b6c"
+	var relayer = "9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b"
+	var netInfo = &coretypes.ResultNetInfo{
+		Listening: true,
+		NPeers:    2,
+		Peers: []coretypes.Peer{
+			{NodeInfo: p2p.DefaultNodeInfo{DefaultNodeID: p2p.ID(sentry), Moniker: "sentry"}, IsOutbound: true, RemoteIP: "10.0.0.1"},
+			{NodeInfo: p2p.DefaultNodeInfo{DefaultNodeID: p2p.ID(relayer), Moniker: "relayer"}, IsOutbound: false, RemoteIP: "10.0.0.2"},
+		},
+	}
+
+	// the configured peers are reported by their lowercase node id
+	var tests = []struct {
+		name   string
+		peer   string
+		wantID string
+		want   float64
+	}{
+		{"connected peer id", sentry, sentry, 1},
+		{"connected peer address", sentry + "@10.0.0.1:26656", sentry, 1},
+		{"connected peer uppercase id", "9A8B7C6D5E4F3A2B1C0D9E8F7A6B5C4D3E2F1A0B@10.0.0.2:26656", relayer, 1},
+		{"disconnected peer", "0000000000000000000000000000000000000000@10.0.0.3:26656", "0000000000000000000000000000000000000000", 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var monitor = newTestMonitor(nil)
+			monitor.target.Peers = []string{test.peer}
+			var registry = prom.NewRegistry()
+			monitor.collectors.Register(registry)
+
+			monitor.updatePeers(testutil.NewRPC(t, map[string]testutil.Method{
+				"net_info": func(params map[string]json.RawMessage) (interface{}, error) {
+					return netInfo, nil
+				},
+			}))
+			for direction, want := range map[string]float64{"inbound": 1, "outbound": 1} {
+				if value, _ := gaugeValue(t, registry, "node_peers", map[string]string{"direction": direction}); value != want {
+					t.Errorf("got %f %s peers, want %f", value, direction, want)
+				}
+			}
+			if _, ok := gaugeValue(t, registry, "node_peer_info", map[string]string{"peer_id": sentry, "moniker": "sentry", "remote_ip": "10.0.0.1", "direction": "outbound"}); !ok {
+				t.Error("got no info of the sentry peer")
+			}
+			if value, ok := gaugeValue(t, registry, "node_peer_connected", map[string]string{"peer_id": test.wantID}); !ok || value != test.want {
+				t.Errorf("got peer %s connected %f (exported %t), want %f", test.wantID, value, ok, test.want)
+			}
+		})
+	}
+
+	// the peers metrics are removed while /net_info is not available
+	var monitor = newTestMonitor(nil)
+	monitor.target.Peers = []string{sentry}
+	var registry = prom.NewRegistry()
+	monitor.collectors.Register(registry)
+	var available = true
+	var client = testutil.NewRPC(t, map[string]testutil.Method{
+		"net_info": func(params map[string]json.RawMessage) (interface{}, error) {
+			if !available {
+				return nil, errors.New("route disabled")
+			}
+			return netInfo, nil
+		},
+	})
+	monitor.updatePeers(client)
+	available = false
+	monitor.updatePeers(client)
+	for _, name := range []string{"node_peers", "node_peer_info", "node_peer_connected"} {
+		if _, ok := gaugeValue(t, registry, name, map[string]string{}); ok {
+			t.Errorf("got %s exported without /net_info", name)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"bytes"
+	ctypes "github.com/tendermint/tendermint/types"
+	"math"
+	"sort"
+)
+
+// topValidatorsRatio is the ratio of the validators set used for the top validators share
+const topValidatorsRatio = 0.33
+
+// sortByVotingPower returns the consensus validators sorted by voting power, then by address as Tendermint does
+func sortByVotingPower(consValidators *[]*ctypes.Validator) []*ctypes.Validator {
+	var sorted = append([]*ctypes.Validator{}, *consValidators...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].VotingPower != sorted[j].VotingPower {
+			return sorted[i].VotingPower > sorted[j].VotingPower
+		}
+		return bytes.Compare(sorted[i].Address, sorted[j].Address) < 0
+	})
+	return sorted
+}
+
+// votingPowerRank returns the 1-based rank by voting power of the validator in the consensus validators
+func votingPowerRank(address ctypes.Address, consValidators *[]*ctypes.Validator) int {
+	for i, validator := range sortByVotingPower(consValidators) {
+		if bytes.Equal(validator.Address, address) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// nakamotoCoefficient returns the minimum number of validators holding more than 1/3 of the voting power, able to halt the chain
+func nakamotoCoefficient(consValidators *[]*ctypes.Validator) int {
+	var totalVotingPower = calculateTotalVotingPower(consValidators)
+	var votingPower int64 = 0
+	for i, validator := range sortByVotingPower(consValidators) {
+		votingPower += validator.VotingPower
+		if votingPower*3 > totalVotingPower {
+			return i + 1
+		}
+	}
+	return len(*consValidators)
+}
+
+// topValidatorsShare returns the share of the voting power held by the given ratio of the top validators
+func topValidatorsShare(consValidators *[]*ctypes.Validator, ratio float64) float64 {
+	var totalVotingPower = calculateTotalVotingPower(consValidators)
+	if totalVotingPower == 0 {
+		return 0
+	}
+	var count = int(math.Ceil(float64(len(*consValidators)) * ratio))
+	var votingPower int64 = 0
+	for _, validator := range sortByVotingPower(consValidators)[:count] {
+		votingPower += validator.VotingPower
+	}
+	return float64(votingPower) / float64(totalVotingPower)
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	ctypes "github.com/tendermint/tendermint/types"
+	"testing"
+)
+
+// powerValidators returns the consensus validators of the given voting powers, their addresses following their order
+func powerValidators(powers ...int64) *[]*ctypes.Validator {
+	var validators = []*ctypes.Validator{}
+	for i, power := range powers {
+		validators = append(validators, &ctypes.Validator{Address: ctypes.Address{byte(i + 1)}, VotingPower: power})
+	}
+	return &validators
+}
+
+func TestNakamotoCoefficient(t *testing.T) {
+	var tests = []struct {
+		name   string
+		powers []int64
+		want   int
+	}{
+		{"empty set", nil, 0},
+		{"single validator", []int64{10}, 1},
+		{"largest validator above 1/3", []int64{34, 33, 33}, 1},
+		{"exactly 1/3 is not enough", []int64{10, 10, 10}, 2},
+		{"unsorted validators", []int64{10, 10, 30, 20, 30}, 2},
+		{"tied validators", []int64{25, 25, 25, 25}, 2},
+	}
+	for _, test := range tests {
+		if got := nakamotoCoefficient(powerValidators(test.powers...)); got != test.want {
+			t.Errorf("%s: got nakamoto coefficient %d, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestTopValidatorsShare(t *testing.T) {
+	// a large validator followed by 99 small ones
+	var hundred = []int64{67}
+	for len(hundred) < 100 {
+		hundred = append(hundred, 1)
+	}
+
+	var tests = []struct {
+		name   string
+		powers []int64
+		want   float64
+	}{
+		{"empty set", nil, 0},
+		{"single validator", []int64{10}, 1},
+		{"33% of 3 validators rounded up to 1", []int64{10, 60, 30}, 0.6},
+		{"33% of 4 validators rounded up to 2", []int64{40, 30, 20, 10}, 0.7},
+		{"33% of 100 validators rounded up to 33", hundred, 99.0 / 166},
+		{"tied validators", []int64{25, 25, 25, 25}, 0.5},
+		{"no voting power", []int64{0, 0, 0}, 0},
+	}
+	for _, test := range tests {
+		if got := topValidatorsShare(powerValidators(test.powers...), topValidatorsRatio); got != test.want {
+			t.Errorf("%s: got top validators share %f, want %f", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package core
+
+import (
+	"simple-exporter/config"
+	"simple-exporter/prometheus"
+)
+
+// Probe updates once the metrics of the given RPC node and validators, serving the /probe requests
+func Probe(collectors *prometheus.Metrics, rpcAddr string, validators []string, cfg *config.Config) error {
+	var target = config.Target{
+		Name:       rpcAddr,
+		RPC:        rpcAddr,
+		Validators: validators,
+	}
+	// probes are stateless, skipping the collectors needing a state across the updates or too heavy for each scrape
+	var monitor = NewMonitor(target, cfg, collectors)
+	monitor.probe = true
+
+	client, err := monitor.connect()
+	defer monitor.disconnect()
+	if err != nil {
+		return err
+	}
+
+	err = monitor.resolveChainID(client)
+	if err != nil {
+		return err
+	}
+	return monitor.UpdateMetrics(client)
+}
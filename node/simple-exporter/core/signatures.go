@@ -0,0 +1,212 @@
+package core
+
+import (
+	tmhttp "github.com/tendermint/tendermint/rpc/client/http"
+	ctypes "github.com/tendermint/tendermint/types"
+	"simple-exporter/prometheus"
+	"simple-exporter/rpc"
+)
+
+// maxBlocksPerUpdate limits the blocks fetched in a single update, while filling the window or catching up
+const maxBlocksPerUpdate = 100
+
+// SignatureTracker tracks the validator signatures and proposals over a sliding window of recent blocks
+type SignatureTracker struct {
+	metrics         *prometheus.Target
+	window          []bool // ring buffer of the signed status of the blocks
+	missedProposals []bool // ring buffer of the blocks expected to be proposed by the validator but proposed by another one
+	next            int    // next position to write in the window
+	count           int    // number of blocks currently in the window
+	streak          int    // current consecutive missed blocks
+	lastHeight      int64  // last processed block height
+}
+
+// NewSignatureTracker creates a SignatureTracker with a window of the given blocks size
+func NewSignatureTracker(metrics *prometheus.Target, windowSize int) *SignatureTracker {
+	if windowSize < 1 {
+		windowSize = 1
+	}
+	return &SignatureTracker{
+		metrics:         metrics,
+		window:          make([]bool, windowSize),
+		missedProposals: make([]bool, windowSize),
+	}
+}
+
+// blockCache fetches the blocks and their validators set once per update, sharing them across the signature trackers
+type blockCache struct {
+	client     *tmhttp.HTTP
+	blocks     map[int64]*cachedBlock
+	validators map[int64]*[]*ctypes.Validator
+}
+
+// cachedBlock holds a block, its validators set and its round 0 proposer
+type cachedBlock struct {
+	block            *ctypes.Block
+	consValidators   *[]*ctypes.Validator
+	expectedProposer *ctypes.Validator
+}
+
+func newBlockCache(client *tmhttp.HTTP) *blockCache {
+	return &blockCache{client: client, blocks: make(map[int64]*cachedBlock), validators: make(map[int64]*[]*ctypes.Validator)}
+}
+
+// get returns the block at the given height, fetching it on the first call
+func (c *blockCache) get(height int64) (*cachedBlock, error) {
+	if cached, ok := c.blocks[height]; ok {
+		return cached, nil
+	}
+	block, err := rpc.GetBlock(c.client, height)
+	if err != nil {
+		return nil, err
+	}
+	consValidators, err := c.getValidators(height)
+	if err != nil {
+		return nil, err
+	}
+	var cached = &cachedBlock{block: block, consValidators: consValidators}
+	// the first block doesn't have a previous validators set, nor a tracked proposal
+	if height > 1 {
+		previousValidators, err := c.getValidators(height - 1)
+		if err != nil {
+			return nil, err
+		}
+		cached.expectedProposer = expectedProposer(previousValidators)
+	}
+	c.blocks[height] = cached
+	return cached, nil
+}
+
+// getValidators returns the validators set at the given height, fetching it on the first call
+func (c *blockCache) getValidators(height int64) (*[]*ctypes.Validator, error) {
+	if consValidators, ok := c.validators[height]; ok {
+		return consValidators, nil
+	}
+	consValidators, err := rpc.GetValidatorsAtHeight(c.client, &height)
+	if err != nil {
+		return nil, err
+	}
+	c.validators[height] = consValidators
+	return consValidators, nil
+}
+
+// Update processes the blocks from the last processed one up to the latest height
+func (t *SignatureTracker) Update(blocks *blockCache, validator prometheus.Validator, validatorAddr ctypes.Address, latestHeight int64) error {
+	var fromHeight = t.lastHeight + 1
+	if latestHeight-fromHeight >= int64(len(t.window)) {
+		fromHeight = latestHeight - int64(len(t.window)) + 1
+	}
+	if latestHeight-fromHeight >= maxBlocksPerUpdate {
+		fromHeight = latestHeight - maxBlocksPerUpdate + 1
+	}
+	// the skipped heights break the consecutive missed blocks, not knowing if they were signed
+	if t.lastHeight > 0 && fromHeight > t.lastHeight+1 {
+		t.streak = 0
+	}
+
+	for height := fromHeight; height <= latestHeight; height++ {
+		cached, err := blocks.get(height)
+		if err != nil {
+			return err
+		}
+		t.lastHeight = height
+
+		// the first block doesn't have a LastCommit, nor a window position
+		if cached.block.LastCommit == nil || len(cached.block.LastCommit.Signatures) == 0 {
+			continue
+		}
+		t.trackProposal(validator, cached, validatorAddr)
+		t.track(validator, isCommitSigned(cached.block.LastCommit, validatorAddr))
+	}
+
+	t.metrics.UpdateMissedBlocksStreak(validator, t.streak)
+	t.metrics.UpdateUptime(validator, t.uptime())
+	t.metrics.UpdateMissedProposals(validator, t.countMissedProposals())
+	return nil
+}
+
+// Skip skips the blocks up to the given height, while the validator is not in the active set.
+// The missed blocks streak is reset, the validator not being expected to sign them
+func (t *SignatureTracker) Skip(validator prometheus.Validator, height int64) {
+	t.lastHeight = height
+	t.streak = 0
+	t.metrics.UpdateMissedBlocksStreak(validator, t.streak)
+}
+
+// track adds the signed status of a block to the window
+func (t *SignatureTracker) track(validator prometheus.Validator, signed bool) {
+	t.window[t.next] = signed
+	t.next = (t.next + 1) % len(t.window)
+	if t.count < len(t.window) {
+		t.count++
+	}
+
+	if signed {
+		t.streak = 0
+	} else {
+		t.streak++
+	}
+	t.metrics.UpdateBlockSigned(validator, signed)
+}
+
+// trackProposal tracks the proposer of a block, comparing it with the expected round 0 proposer.
+// It must be called before track, sharing its window position
+func (t *SignatureTracker) trackProposal(validator prometheus.Validator, cached *cachedBlock, validatorAddr ctypes.Address) {
+	var proposed = cached.block.ProposerAddress.String() == validatorAddr.String()
+	var expectedProposer = cached.expectedProposer
+	t.missedProposals[t.next] = !proposed && expectedProposer != nil && expectedProposer.Address.String() == validatorAddr.String()
+
+	var consValidator = getConsValidatorFromAddress(validatorAddr.String(), cached.consValidators)
+	var share = 0.0
+	if consValidator != nil {
+		share = float64(consValidator.VotingPower) / float64(calculateTotalVotingPower(cached.consValidators))
+	}
+	t.metrics.UpdateBlockProposed(validator, proposed, share)
+}
+
+// uptime returns the ratio of signed blocks in the window
+func (t *SignatureTracker) uptime() float64 {
+	if t.count == 0 {
+		return 0
+	}
+	var signed = 0
+	for i := 0; i < t.count; i++ {
+		if t.window[i] {
+			signed++
+		}
+	}
+	return float64(signed) / float64(t.count)
+}
+
+// countMissedProposals returns the blocks in the window expected to be proposed by the validator but proposed by another one
+func (t *SignatureTracker) countMissedProposals() int {
+	var missed = 0
+	for i := 0; i < t.count; i++ {
+		if t.missedProposals[i] {
+			missed++
+		}
+	}
+	return missed
+}
+
+// isCommitSigned checks if the validator signature is inside the commit.
+// As for the slashing module, nil votes are considered signed
+func isCommitSigned(commit *ctypes.Commit, validatorAddr ctypes.Address) bool {
+	for _, sig := range commit.Signatures {
+		if !sig.Absent() && sig.ValidatorAddress.String() == validatorAddr.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// expectedProposer returns the round 0 proposer of a block from the validators set of the previous height.
+// The validators sets are returned with the priorities already incremented for their own height,
+// so the proposer is the one selected by incrementing the previous height priorities once
+func expectedProposer(previousValidators *[]*ctypes.Validator) *ctypes.Validator {
+	if len(*previousValidators) == 0 {
+		return nil
+	}
+	var validatorSet = &ctypes.ValidatorSet{Validators: *previousValidators}
+	return validatorSet.CopyIncrementProposerPriority(1).Proposer
+}
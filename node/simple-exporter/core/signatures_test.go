@@ -0,0 +1,190 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	tmhttp "github.com/tendermint/tendermint/rpc/client/http"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	ctypes "github.com/tendermint/tendermint/types"
+	"simple-exporter/prometheus"
+	"simple-exporter/testutil"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeBlocks serves the blocks of a chain of two validators, the first one signing all the blocks and the second one
+// none, all the blocks being proposed at round 1 by the validator following the expected round 0 proposer.
+// It counts the blocks and validators sets requests
+type fakeBlocks struct {
+	latest           int64
+	validators       []*ctypes.Validator
+	sets             []*ctypes.ValidatorSet // validators sets by height, with the priorities incremented for their height
+	blockQueries     atomic.Int64
+	validatorQueries atomic.Int64
+}
+
+func newFakeBlocks(latest int64) *fakeBlocks {
+	var signer = ctypes.NewValidator(ed25519.GenPrivKeyFromSecret([]byte("signer")).PubKey(), 20)
+	var absent = ctypes.NewValidator(ed25519.GenPrivKeyFromSecret([]byte("absent")).PubKey(), 10)
+	// the new set is incremented once, holding the proposer of the first height
+	var sets = []*ctypes.ValidatorSet{nil, ctypes.NewValidatorSet([]*ctypes.Validator{signer, absent})}
+	for height := int64(2); height <= latest; height++ {
+		sets = append(sets, sets[height-1].CopyIncrementProposerPriority(1))
+	}
+	return &fakeBlocks{latest: latest, validators: []*ctypes.Validator{signer, absent}, sets: sets}
+}
+
+// expectedProposer returns the round 0 proposer of the given height
+func (b *fakeBlocks) expectedProposer(height int64) ctypes.Address {
+	return b.sets[height].Proposer.Address
+}
+
+// block returns the block at the given height, the first one not having a LastCommit
+func (b *fakeBlocks) block(height int64) *ctypes.Block {
+	var proposer = b.validators[0].Address
+	if proposer.String() == b.expectedProposer(height).String() {
+		proposer = b.validators[1].Address
+	}
+	var block = &ctypes.Block{Header: ctypes.Header{ChainID: "test-1", Height: height, ProposerAddress: proposer}}
+	block.LastCommit = &ctypes.Commit{}
+	if height > 1 {
+		block.LastCommit = &ctypes.Commit{Height: height - 1, Signatures: []ctypes.CommitSig{
+			{BlockIDFlag: ctypes.BlockIDFlagCommit, ValidatorAddress: b.validators[0].Address, Timestamp: time.Unix(height, 0).UTC(), Signature: []byte("signature")},
+			ctypes.NewCommitSigAbsent(),
+		}}
+	}
+	return block
+}
+
+// missedProposals returns the blocks of the given heights expected to be proposed by the validator
+func (b *fakeBlocks) missedProposals(validatorAddr ctypes.Address, fromHeight int64, toHeight int64) int {
+	var missed = 0
+	for height := fromHeight; height <= toHeight; height++ {
+		if b.expectedProposer(height).String() == validatorAddr.String() {
+			missed++
+		}
+	}
+	return missed
+}
+
+func (b *fakeBlocks) client(t *testing.T) *tmhttp.HTTP {
+	return testutil.NewRPC(t, map[string]testutil.Method{
+		"block": func(params map[string]json.RawMessage) (interface{}, error) {
+			b.blockQueries.Add(1)
+			var height = testutil.Int64Param(params, "height")
+			if height < 1 || height > b.latest {
+				return nil, errors.New(fmt.Sprintf("height %d is not available", height))
+			}
+			return &coretypes.ResultBlock{Block: b.block(height)}, nil
+		},
+		"validators": func(params map[string]json.RawMessage) (interface{}, error) {
+			b.validatorQueries.Add(1)
+			var height = testutil.Int64Param(params, "height")
+			if height < 1 || height > b.latest {
+				return nil, errors.New(fmt.Sprintf("height %d is not available", height))
+			}
+			var validators = b.sets[height].Validators
+			return &coretypes.ResultValidators{BlockHeight: height, Validators: validators, Count: len(validators), Total: len(validators)}, nil
+		},
+	})
+}
+
+func TestSignatureTrackerUpdate(t *testing.T) {
+	var chain = newFakeBlocks(5)
+	var metrics = prometheus.NewMetrics().NewTarget("test-1", "test")
+	var blocks = newBlockCache(chain.client(t))
+
+	var signer = NewSignatureTracker(metrics, 10)
+	err := signer.Update(blocks, prometheus.Validator{Moniker: "signer"}, chain.validators[0].Address, chain.latest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var absent = NewSignatureTracker(metrics, 10)
+	err = absent.Update(blocks, prometheus.Validator{Moniker: "absent"}, chain.validators[1].Address, chain.latest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the blocks and their validators sets are fetched once for all the validators
+	if chain.blockQueries.Load() != chain.latest || chain.validatorQueries.Load() != chain.latest {
+		t.Errorf("got %d blocks and %d validators queries, want %d each", chain.blockQueries.Load(), chain.validatorQueries.Load(), chain.latest)
+	}
+
+	// the first block, without a LastCommit, is not tracked
+	if signer.count != 4 || signer.uptime() != 1 {
+		t.Errorf("got %d blocks and %f uptime for the signer", signer.count, signer.uptime())
+	}
+	if absent.count != 4 || absent.uptime() != 0 || absent.streak != 4 {
+		t.Errorf("got %d blocks, %f uptime and %d missed streak for the absent validator", absent.count, absent.uptime(), absent.streak)
+	}
+
+	// the missed proposals are the blocks where the validator is the round 0 proposer of the height
+	for i, tracker := range []*SignatureTracker{signer, absent} {
+		for position := 0; position < tracker.count; position++ {
+			var height = int64(position) + 2
+			var expected = chain.expectedProposer(height).String() == chain.validators[i].Address.String()
+			if tracker.missedProposals[position] != expected {
+				t.Errorf("got missed proposal %t for validator %d at height %d, want %t", tracker.missedProposals[position], i, height, expected)
+			}
+		}
+		if tracker.countMissedProposals() != chain.missedProposals(chain.validators[i].Address, 2, chain.latest) {
+			t.Errorf("got %d missed proposals for validator %d, want %d", tracker.countMissedProposals(), i, chain.missedProposals(chain.validators[i].Address, 2, chain.latest))
+		}
+	}
+	if signer.countMissedProposals() == 0 || absent.countMissedProposals() == 0 {
+		t.Errorf("got %d and %d missed proposals, want both validators expected to propose", signer.countMissedProposals(), absent.countMissedProposals())
+	}
+}
+
+func TestSignatureTrackerFirstBlock(t *testing.T) {
+	var chain = newFakeBlocks(1)
+	var tracker = NewSignatureTracker(prometheus.NewMetrics().NewTarget("test-1", "test"), 10)
+	err := tracker.Update(newBlockCache(chain.client(t)), prometheus.Validator{Moniker: "signer"}, chain.expectedProposer(1), chain.latest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the missed proposal of the first block doesn't take a window position
+	if tracker.count != 0 || tracker.next != 0 || tracker.missedProposals[0] {
+		t.Errorf("got the first block tracked at window position %d (count %d, missed proposal %t)", tracker.next, tracker.count, tracker.missedProposals[0])
+	}
+	if tracker.lastHeight != 1 {
+		t.Errorf("got last height %d, want 1", tracker.lastHeight)
+	}
+}
+
+func TestSignatureTrackerSkippedHeights(t *testing.T) {
+	var metrics = prometheus.NewMetrics().NewTarget("test-1", "test")
+	var labels = prometheus.Validator{Moniker: "absent"}
+	var update = func(tracker *SignatureTracker, latest int64) {
+		t.Helper()
+		var chain = newFakeBlocks(latest)
+		err := tracker.Update(newBlockCache(chain.client(t)), labels, chain.validators[1].Address, latest)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the absent validator never signs, missing the blocks 2 to 5
+	var tracker = NewSignatureTracker(metrics, 10)
+	update(tracker, 5)
+	if tracker.streak != 4 {
+		t.Fatalf("got missed streak %d, want 4", tracker.streak)
+	}
+
+	// the heights skipped beyond the window reset the streak, only the window blocks being missed
+	update(tracker, 300)
+	if tracker.streak != 10 {
+		t.Errorf("got missed streak %d after skipped heights, want 10", tracker.streak)
+	}
+
+	// the blocks while out of the active set are not tracked
+	tracker.Skip(labels, 310)
+	update(tracker, 312)
+	if tracker.streak != 2 || tracker.lastHeight != 312 {
+		t.Errorf("got missed streak %d at height %d after rejoining the active set, want 2 at 312", tracker.streak, tracker.lastHeight)
+	}
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"fmt"
+	tmhttp "github.com/tendermint/tendermint/rpc/client/http"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	"simple-exporter/prometheus"
+	"simple-exporter/rpc"
+	"time"
+)
+
+// updateSyncInfo updates the node sync info metrics, comparing the node blocks with the reference nodes ones
+func (m *Monitor) updateSyncInfo(client *tmhttp.HTTP, nodeInfo *coretypes.ResultStatus) {
+	var syncInfo = nodeInfo.SyncInfo
+	m.blockTime = syncInfo.LatestBlockTime
+	m.metrics.UpdateSyncInfo(syncInfo.LatestBlockHeight, syncInfo.LatestBlockTime, syncInfo.CatchingUp, syncInfo.EarliestBlockHeight)
+
+	// the lag behind the best reference tells a node behind from a slow chain
+	var references = m.referencesStatus()
+	var best = bestReference(references)
+	if best != nil {
+		m.metrics.UpdateReferenceLag(best.LatestBlockHeight-syncInfo.LatestBlockHeight, best.LatestBlockTime.Sub(syncInfo.LatestBlockTime).Seconds())
+	} else {
+		m.metrics.DeleteReferenceLag()
+	}
+	m.updateDivergences(client, nodeInfo, references)
+	m.updateBlockStatus(syncInfo.LatestBlockTime, references)
+}
+
+// updateUnavailableSyncInfo updates the latest block age and the blocks status from the last known block time,
+// while the node status is not available, so that an unreachable node doesn't freeze them
+func (m *Monitor) updateUnavailableSyncInfo() {
+	if m.blockTime.IsZero() {
+		return
+	}
+	m.metrics.UpdateSecondsSinceLastBlock(m.blockTime)
+	m.updateBlockStatus(m.blockTime, m.referencesStatus())
+}
+
+// updateBlockStatus classifies the node blocks status from its latest block time and the reference nodes ones
+func (m *Monitor) updateBlockStatus(latestBlockTime time.Time, references []referenceStatus) {
+	var status = prometheus.BlockStatusOK
+	if time.Since(latestBlockTime) > m.config.BlockStaleThreshold.Duration() {
+		status = m.staleBlockStatus(references)
+		m.logger.Println(fmt.Sprintf("No new blocks since %s, blocks status: %s", latestBlockTime.Format(time.RFC3339), status))
+	}
+	m.metrics.UpdateBlockStatus(status)
+}
+
+// referenceStatus holds the sync info of an available reference node
+type referenceStatus struct {
+	endpoint string
+	client   *tmhttp.HTTP
+	syncInfo coretypes.SyncInfo
+}
+
+// referencesStatus returns the status of the available reference nodes, synced with the target chain
+func (m *Monitor) referencesStatus() []referenceStatus {
+	var references []referenceStatus
+	for i, reference := range m.references {
+		status, err := rpc.GetNodeInfo(reference)
+		if err != nil {
+			m.logger.Println(fmt.Sprintf("Cannot get the reference '%s' status (%s)", m.target.References[i], err.Error()))
+			continue
+		}
+		// the references of other chains or still syncing can't tell the chain status
+		if status.NodeInfo.Network != m.chainID || status.SyncInfo.CatchingUp {
+			m.logger.Println(fmt.Sprintf("Reference '%s' is not synced with the '%s' chain", m.target.References[i], m.chainID))
+			continue
+		}
+		references = append(references, referenceStatus{endpoint: m.target.References[i], client: reference, syncInfo: status.SyncInfo})
+	}
+	return references
+}
+
+// bestReference returns the sync info of the reference with the highest latest block, nil if no reference is available
+func bestReference(references []referenceStatus) *coretypes.SyncInfo {
+	var best *coretypes.SyncInfo
+	for i := range references {
+		if best == nil || references[i].syncInfo.LatestBlockHeight > best.LatestBlockHeight {
+			best = &references[i].syncInfo
+		}
+	}
+	return best
+}
+
+// staleBlockStatus tells a stuck node from a halted chain, checking if the reference nodes receive new blocks
+func (m *Monitor) staleBlockStatus(references []referenceStatus) string {
+	if len(references) == 0 {
+		return prometheus.BlockStatusStale
+	}
+	for _, reference := range references {
+		if time.Since(reference.syncInfo.LatestBlockTime) <= m.config.BlockStaleThreshold.Duration() {
+			return prometheus.BlockStatusStuck
+		}
+	}
+	return prometheus.BlockStatusHalted
+}
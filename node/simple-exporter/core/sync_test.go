@@ -0,0 +1,79 @@
+package core
+
+import (
+	"encoding/json"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/tendermint/tendermint/p2p"
+	tmhttp "github.com/tendermint/tendermint/rpc/client/http"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	"simple-exporter/config"
+	"simple-exporter/testutil"
+	"testing"
+	"time"
+)
+
+// gaugeValue returns the value of the gathered gauge with the given labels, false if not exported
+func gaugeValue(t *testing.T, registry *prom.Registry, name string, labels map[string]string) (float64, bool) {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			var matched = 0
+			for _, label := range metric.GetLabel() {
+				if value, ok := labels[label.GetName()]; ok && value == label.GetValue() {
+					matched++
+				}
+			}
+			if matched == len(labels) {
+				return metric.GetGauge().GetValue(), true
+			}
+		}
+	}
+	return 0, false
+}
+
+func TestUnavailableSyncInfo(t *testing.T) {
+	var reference = testutil.NewRPC(t, map[string]testutil.Method{
+		"status": func(params map[string]json.RawMessage) (interface{}, error) {
+			return &coretypes.ResultStatus{NodeInfo: p2p.DefaultNodeInfo{Network: "test-1"}, SyncInfo: coretypes.SyncInfo{LatestBlockHeight: 200, LatestBlockTime: time.Now()}}, nil
+		},
+	})
+	var monitor = newTestMonitor(nil)
+	monitor.config.BlockStaleThreshold = config.Duration(time.Minute)
+	monitor.target.References = []string{"reference"}
+	monitor.references = []*tmhttp.HTTP{reference}
+	var registry = prom.NewRegistry()
+	monitor.collectors.Register(registry)
+
+	// the node status is not available, since 5 minutes after its latest block
+	monitor.blockTime = time.Now().Add(-5 * time.Minute)
+	err := monitor.UpdateMetrics(testutil.NewRPC(t, nil))
+	if err == nil {
+		t.Fatal("got the metrics updated without the node status")
+	}
+
+	// the latest block age and status are still updated, the references telling a stuck node
+	age, ok := gaugeValue(t, registry, "node_seconds_since_last_block", nil)
+	if !ok || age < 300 {
+		t.Errorf("got latest block age %f (%t), want 300s at least", age, ok)
+	}
+	stuck, ok := gaugeValue(t, registry, "node_block_status", map[string]string{"status": "stuck"})
+	if !ok || stuck != 1 {
+		t.Errorf("got stuck status %f (%t), want 1", stuck, ok)
+	}
+
+	// nothing is exported before knowing the latest block
+	monitor = newTestMonitor(nil)
+	registry = prom.NewRegistry()
+	monitor.collectors.Register(registry)
+	_ = monitor.UpdateMetrics(testutil.NewRPC(t, nil))
+	if _, ok = gaugeValue(t, registry, "node_block_status", nil); ok {
+		t.Error("got a block status without any known block")
+	}
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"fmt"
+	tmhttp "github.com/tendermint/tendermint/rpc/client/http"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	"simple-exporter/abci"
+	"simple-exporter/rpc"
+	"time"
+)
+
+// averageBlockTimeBlocks is the number of recent blocks used to calculate the average block time
+const averageBlockTimeBlocks = 100
+
+// updateUpgradePlan updates the scheduled upgrade plan metrics, estimating the time to the upgrade from the average block time
+func (m *Monitor) updateUpgradePlan(client *tmhttp.HTTP, nodeInfo *coretypes.ResultStatus) error {
+	averageBlockTime, err := calculateAverageBlockTime(client, nodeInfo)
+	if err != nil {
+		return err
+	}
+	m.metrics.UpdateAverageBlockTime(averageBlockTime)
+
+	plan, err := abci.GetCurrentPlan(m.querier)
+	if err != nil {
+		m.logger.Println(fmt.Sprintf("Cannot get the upgrade plan (%s)", err.Error()))
+		return nil
+	}
+	if m.target.DaemonHome != "" {
+		m.updateCosmovisor(plan)
+	}
+	if plan == nil {
+		m.metrics.DeleteUpgradePlan()
+		// the last seen plan is not scheduled anymore, check if it has been applied
+		if m.upgradePlan != "" {
+			height, err := abci.GetAppliedPlan(m.querier, m.upgradePlan)
+			if err != nil {
+				return err
+			}
+			if height > 0 {
+				m.logger.Println(fmt.Sprintf("Upgrade '%s' applied at height %d", m.upgradePlan, height))
+				m.metrics.UpdateUpgradeApplied(m.upgradePlan, height)
+			}
+			m.upgradePlan = ""
+		}
+		return nil
+	}
+
+	var blocksRemaining = plan.Height - nodeInfo.SyncInfo.LatestBlockHeight
+	if blocksRemaining < 0 {
+		blocksRemaining = 0
+	}
+	m.upgradePlan = plan.Name
+	m.metrics.UpdateUpgradePlan(plan.Name, plan.Height, blocksRemaining, time.Duration(blocksRemaining)*averageBlockTime)
+	return nil
+}
+
+// calculateAverageBlockTime calculates the average block time over the recent blocks
+func calculateAverageBlockTime(client *tmhttp.HTTP, nodeInfo *coretypes.ResultStatus) (time.Duration, error) {
+	var latestHeight = nodeInfo.SyncInfo.LatestBlockHeight
+	var fromHeight = latestHeight - averageBlockTimeBlocks
+	if fromHeight < nodeInfo.SyncInfo.EarliestBlockHeight {
+		fromHeight = nodeInfo.SyncInfo.EarliestBlockHeight
+	}
+	if fromHeight >= latestHeight {
+		return 0, nil
+	}
+
+	header, err := rpc.GetBlockHeader(client, fromHeight)
+	if err != nil {
+		return 0, err
+	}
+	return nodeInfo.SyncInfo.LatestBlockTime.Sub(header.Time) / time.Duration(latestHeight-fromHeight), nil
+}
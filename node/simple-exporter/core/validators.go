@@ -0,0 +1,230 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	bech322 "github.com/cosmos/cosmos-sdk/types/bech32"
+	stakingTypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	ctypes "github.com/tendermint/tendermint/types"
+	"google.golang.org/protobuf/encoding/protowire"
+	"simple-exporter/prometheus"
+	"strings"
+)
+
+// monitoredValidator holds the resolved info of a monitored validator
+type monitoredValidator struct {
+	moniker       string
+	valoper       string // the provider valoper on the ICS consumer chains
+	valcons       string
+	consValidator *ctypes.Validator       // nil if the validator is not in the active set
+	validator     *stakingTypes.Validator // nil if the staking validators are not available (ICS chain)
+}
+
+func (v *monitoredValidator) labels() prometheus.Validator {
+	return prometheus.Validator{
+		Moniker: v.moniker,
+		Valcons: v.valcons,
+	}
+}
+
+// resolveValidators resolves the monitored validators from the consensus and staking validators.
+// If no validators are configured, the node validator is used
+func (m *Monitor) resolveValidators(nodeInfo *coretypes.ResultStatus, consValidators *[]*ctypes.Validator, validators *[]stakingTypes.Validator, bech32Prefix string) ([]*monitoredValidator, error) {
+	if len(m.target.Validators) == 0 {
+		// get the wanted Consensus validator
+		var consValidator = getConsValidatorFromAddress(nodeInfo.ValidatorInfo.Address.String(), consValidators)
+		if consValidator == nil {
+			m.logger.Println(fmt.Sprintf("Node '%s' is not a Validator", nodeInfo.NodeInfo.Moniker))
+			return nil, nil
+		}
+
+		// retrieve the validator from the abci validators query from the consensus one
+		var validator *stakingTypes.Validator
+		if validators != nil {
+			validator = retrieveValidator(consValidator, consValidators, validators)
+			if validator == nil {
+				return nil, errors.New("cannot retrieve Validator from ConsValidator")
+			}
+		}
+
+		resolved, err := newMonitoredValidator(consValidator.Address, consValidator, validator, bech32Prefix)
+		if err != nil {
+			return nil, err
+		}
+		if validator == nil {
+			resolved.moniker = nodeInfo.NodeInfo.Moniker
+		}
+		return []*monitoredValidator{resolved}, nil
+	}
+
+	var resolved []*monitoredValidator
+	for _, identifier := range m.target.Validators {
+		validator, err := resolveValidator(identifier, consValidators, validators, bech32Prefix)
+		if err != nil {
+			return nil, err
+		}
+		if validator == nil {
+			m.logger.Println(fmt.Sprintf("Cannot find the validator '%s'", identifier))
+			continue
+		}
+		resolved = append(resolved, validator)
+	}
+	return resolved, nil
+}
+
+// resolveValidator resolves a validator from its valoper address, valcons address or moniker
+func resolveValidator(identifier string, consValidators *[]*ctypes.Validator, validators *[]stakingTypes.Validator, bech32Prefix string) (*monitoredValidator, error) {
+	var validator *stakingTypes.Validator
+	var consAddress ctypes.Address
+
+	hrp, addressBytes, err := bech322.DecodeAndConvert(identifier)
+	switch {
+	case err == nil && hrp == bech32Prefix+"valcons":
+		consAddress = addressBytes
+		if validators != nil {
+			validator = findValidatorByConsAddress(consAddress, consValidators, validators)
+		}
+	case err == nil && hrp == bech32Prefix+"valoper":
+		if validators == nil {
+			return nil, errors.New(fmt.Sprintf("cannot resolve validator '%s' without the staking validators", identifier))
+		}
+		validator = findValidatorByOperator(identifier, validators)
+	default:
+		if validators == nil {
+			return nil, errors.New(fmt.Sprintf("cannot resolve validator '%s' without the staking validators", identifier))
+		}
+		validator, err = findValidatorByMoniker(identifier, validators)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if consAddress == nil {
+		if validator == nil {
+			return nil, nil
+		}
+		consAddress, err = validatorConsAddress(validator, consValidators)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var consValidator = getConsValidatorFromAddress(consAddress.String(), consValidators)
+	return newMonitoredValidator(consAddress, consValidator, validator, bech32Prefix)
+}
+
+// newMonitoredValidator creates a monitoredValidator, calculating its "valcons" address
+func newMonitoredValidator(consAddress ctypes.Address, consValidator *ctypes.Validator, validator *stakingTypes.Validator, bech32Prefix string) (*monitoredValidator, error) {
+	valConsAddr, err := bech322.ConvertAndEncode(bech32Prefix+"valcons", consAddress.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	var moniker = ""
+	var valoper = ""
+	if validator != nil {
+		moniker = validator.GetMoniker()
+		valoper = validator.OperatorAddress
+	}
+	return &monitoredValidator{
+		moniker:       moniker,
+		valoper:       valoper,
+		valcons:       valConsAddr,
+		consValidator: consValidator,
+		validator:     validator,
+	}, nil
+}
+
+// operatorAccountAddress converts the validator operator address to its account address, re-encoding it with the account bech32 prefix
+func operatorAccountAddress(valoper string, bech32Prefix string) (string, error) {
+	_, addressBytes, err := bech322.DecodeAndConvert(valoper)
+	if err != nil {
+		return "", err
+	}
+	return bech322.ConvertAndEncode(bech32Prefix, addressBytes)
+}
+
+func findValidatorByOperator(valoper string, validators *[]stakingTypes.Validator) *stakingTypes.Validator {
+	for i := range *validators {
+		if (*validators)[i].OperatorAddress == valoper {
+			return &(*validators)[i]
+		}
+	}
+	return nil
+}
+
+// findValidatorByMoniker finds the validator with the given moniker, case insensitive.
+// The monikers are not unique, more matching validators are rejected instead of monitoring the wrong one
+func findValidatorByMoniker(moniker string, validators *[]stakingTypes.Validator) (*stakingTypes.Validator, error) {
+	var found *stakingTypes.Validator
+	var operators []string
+	for i := range *validators {
+		if strings.EqualFold(strings.TrimSpace((*validators)[i].GetMoniker()), strings.TrimSpace(moniker)) {
+			found = &(*validators)[i]
+			operators = append(operators, found.OperatorAddress)
+		}
+	}
+	if len(operators) > 1 {
+		return nil, errors.New(fmt.Sprintf("moniker '%s' matches %d validators (%s), use the valoper or valcons address", moniker, len(operators), strings.Join(operators, ", ")))
+	}
+	return found, nil
+}
+
+func findValidatorByConsAddress(consAddress ctypes.Address, consValidators *[]*ctypes.Validator, validators *[]stakingTypes.Validator) *stakingTypes.Validator {
+	for i := range *validators {
+		address, err := validatorConsAddress(&(*validators)[i], consValidators)
+		if err == nil && address.String() == consAddress.String() {
+			return &(*validators)[i]
+		}
+	}
+	return nil
+}
+
+// validatorConsAddress returns the consensus address of a staking validator, derived from its consensus pubkey.
+// The pubkeys of the types unknown to the exporter are matched by their raw bytes with the consensus validators ones
+func validatorConsAddress(validator *stakingTypes.Validator, consValidators *[]*ctypes.Validator) (ctypes.Address, error) {
+	pubKey, err := validator.ConsPubKey()
+	if err == nil {
+		return pubKey.Address(), nil
+	}
+	var keyBytes = validatorPubKeyBytes(validator)
+	if keyBytes != nil {
+		for _, consValidator := range *consValidators {
+			if consValidator.PubKey != nil && bytes.Equal(consValidator.PubKey.Bytes(), keyBytes) {
+				return consValidator.Address, nil
+			}
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("cannot derive the consensus address of '%s' from its consensus pubkey", validator.OperatorAddress))
+}
+
+// validatorPubKeyBytes returns the raw bytes of a packed consensus pubkey, nil if not decodable.
+// The Cosmos-Sdk pubkeys are all encoded as the "key" bytes field 1
+func validatorPubKeyBytes(validator *stakingTypes.Validator) []byte {
+	if validator.ConsensusPubkey == nil {
+		return nil
+	}
+	var data = validator.ConsensusPubkey.Value
+	for len(data) > 0 {
+		number, wireType, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil
+		}
+		data = data[n:]
+		if number == 1 && wireType == protowire.BytesType {
+			keyBytes, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return nil
+			}
+			return keyBytes
+		}
+		n = protowire.ConsumeFieldValue(number, wireType, data)
+		if n < 0 {
+			return nil
+		}
+		data = data[n:]
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	stakingTypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	ctypes "github.com/tendermint/tendermint/types"
+	"google.golang.org/protobuf/encoding/protowire"
+	"simple-exporter/rpc"
+	"simple-exporter/testutil"
+	"strings"
+	"testing"
+)
+
+func TestFindValidatorByMoniker(t *testing.T) {
+	var validators = []stakingTypes.Validator{
+		{OperatorAddress: "cosmosvaloper1a", Description: stakingTypes.Description{Moniker: "Validator A"}},
+		{OperatorAddress: "cosmosvaloper1b", Description: stakingTypes.Description{Moniker: "Validator B "}},
+		{OperatorAddress: "cosmosvaloper1c", Description: stakingTypes.Description{Moniker: "validator b"}},
+	}
+
+	var tests = []struct {
+		moniker string
+		want    string // operator address of the found validator, empty if not found
+		wantErr string
+	}{
+		{moniker: "Validator A", want: "cosmosvaloper1a"},
+		{moniker: " validator a", want: "cosmosvaloper1a"},
+		{moniker: "Validator C"},
+		{moniker: "Validator B", wantErr: "moniker 'Validator B' matches 2 validators (cosmosvaloper1b, cosmosvaloper1c)"},
+	}
+	for _, test := range tests {
+		validator, err := findValidatorByMoniker(test.moniker, &validators)
+		if test.wantErr != "" {
+			if err == nil || !strings.HasPrefix(err.Error(), test.wantErr) {
+				t.Errorf("findValidatorByMoniker(%s) got error %v, want '%s'", test.moniker, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("findValidatorByMoniker(%s) got error %s", test.moniker, err.Error())
+			continue
+		}
+		var got = ""
+		if validator != nil {
+			got = validator.OperatorAddress
+		}
+		if got != test.want {
+			t.Errorf("findValidatorByMoniker(%s) got '%s', want '%s'", test.moniker, got, test.want)
+		}
+	}
+}
+
+func TestValidatorConsAddressUnknownKeyType(t *testing.T) {
+	// a BLS12-381 consensus key, unknown to the exporter Cosmos-Sdk and Tendermint versions
+	var blsKey = bytes.Repeat([]byte{0xb1}, 48)
+	var blsAddress = ctypes.Address(tmhash.SumTruncated(blsKey))
+	var client = testutil.NewRPC(t, map[string]testutil.Method{
+		"validators": func(params map[string]json.RawMessage) (interface{}, error) {
+			return json.RawMessage(fmt.Sprintf(`{"block_height":"100","validators":[{"address":"%s","pub_key":{"type":"cometbft/PubKeyBls12_381","value":"%s"},"voting_power":"10","proposer_priority":"0"}],"count":"1","total":"1"}`,
+				blsAddress, base64.StdEncoding.EncodeToString(blsKey))), nil
+		},
+	})
+	consValidators, err := rpc.GetValidators(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the staking pubkey can't be unpacked, its raw bytes are matched with the consensus validators ones
+	var validator = &stakingTypes.Validator{
+		OperatorAddress: "cosmosvaloper1bls",
+		ConsensusPubkey: &codectypes.Any{TypeUrl: "/cosmos.crypto.bls12_381.PubKey", Value: protowire.AppendBytes(protowire.AppendTag(nil, 1, protowire.BytesType), blsKey)},
+	}
+	address, err := validatorConsAddress(validator, consValidators)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(address, blsAddress) {
+		t.Errorf("got consensus address %s, want %s", address, blsAddress)
+	}
+}
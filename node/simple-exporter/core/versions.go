@@ -0,0 +1,108 @@
+package core
+
+import (
+	"fmt"
+	tmhttp "github.com/tendermint/tendermint/rpc/client/http"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	"regexp"
+	"simple-exporter/abci"
+	"simple-exporter/rpc"
+	"strconv"
+)
+
+// nodeVersions holds the software versions of the target node, used to adapt the queries to the chain
+type nodeVersions struct {
+	cometBFT   string // Tendermint/CometBFT version, from /status
+	appName    string // application name, from /abci_info
+	appVersion string // application version, from /abci_info
+	sdk        string // Cosmos-Sdk version, from the node info query, empty if not available
+}
+
+// latest tested versions, the newer ones are monitored with a warning
+var (
+	latestCometBFT = [2]int{0, 38}
+	latestSDK      = [2]int{0, 50}
+)
+
+var versionRegex = regexp.MustCompile(`^v?(\d+)\.(\d+)`)
+
+// parseVersion extracts the major and minor numbers of a version (ex. v0.47.5-ics), false if not parsable
+func parseVersion(version string) ([2]int, bool) {
+	var matches = versionRegex.FindStringSubmatch(version)
+	if matches == nil {
+		return [2]int{}, false
+	}
+	major, _ := strconv.Atoi(matches[1])
+	minor, _ := strconv.Atoi(matches[2])
+	return [2]int{major, minor}, true
+}
+
+// compareVersions compares two major.minor versions, returning -1, 0 or 1
+func compareVersions(a [2]int, b [2]int) int {
+	for i := range a {
+		if a[i] < b[i] {
+			return -1
+		}
+		if a[i] > b[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// sdkBefore checks if the Cosmos-Sdk version is known and older than the given one, so that the newer modules can be skipped.
+// An unknown version is not assumed recent: the newer modules are tried first, falling back to the older ones
+func (v *nodeVersions) sdkBefore(major int, minor int) bool {
+	if v == nil {
+		return false
+	}
+	version, ok := parseVersion(v.sdk)
+	return ok && compareVersions(version, [2]int{major, minor}) < 0
+}
+
+// untested returns the node software newer than the tested versions, if any
+func (v *nodeVersions) untested() []string {
+	var untested []string
+	if version, ok := parseVersion(v.cometBFT); ok && compareVersions(version, latestCometBFT) > 0 {
+		untested = append(untested, fmt.Sprintf("CometBFT %s", v.cometBFT))
+	}
+	if version, ok := parseVersion(v.sdk); ok && compareVersions(version, latestSDK) > 0 {
+		untested = append(untested, fmt.Sprintf("Cosmos-Sdk %s", v.sdk))
+	}
+	return untested
+}
+
+// updateVersions detects the node software versions, detecting them again when the node is upgraded
+func (m *Monitor) updateVersions(client *tmhttp.HTTP, nodeInfo *coretypes.ResultStatus) error {
+	abciInfo, err := rpc.GetABCIInfo(client)
+	if err != nil {
+		return err
+	}
+	var versions = nodeVersions{
+		cometBFT:   nodeInfo.NodeInfo.Version,
+		appName:    abciInfo.Response.Data,
+		appVersion: abciInfo.Response.Version,
+	}
+	if m.versions != nil && m.versions.cometBFT == versions.cometBFT && m.versions.appName == versions.appName && m.versions.appVersion == versions.appVersion {
+		return nil
+	}
+
+	// the Cosmos-Sdk version is not in the ABCI info, only in the node info query
+	appVersionInfo, err := abci.GetAppVersionInfo(m.querier)
+	if err != nil {
+		m.logger.Println(fmt.Sprintf("Cannot get the Cosmos-Sdk version (%s), the newer modules are tried first", err.Error()))
+	} else {
+		versions.sdk = appVersionInfo.CosmosSdkVersion
+	}
+	m.logger.Println(fmt.Sprintf("Detected versions: CometBFT '%s', %s '%s', Cosmos-Sdk '%s'", versions.cometBFT, versions.appName, versions.appVersion, versions.sdk))
+	for _, software := range versions.untested() {
+		m.logger.Println(fmt.Sprintf("Warning: %s is newer than the tested versions, some metrics may be missing", software))
+	}
+
+	if m.versions != nil {
+		m.metrics.DeleteNodeVersions(m.versions.cometBFT, m.versions.appName, m.versions.appVersion, m.versions.sdk)
+	}
+	m.metrics.UpdateNodeVersions(versions.cometBFT, versions.appName, versions.appVersion, versions.sdk)
+	m.versions = &versions
+	return nil
+}
@@ -0,0 +1,125 @@
+package core
+
+import (
+	"context"
+	"simple-exporter/abci"
+	"simple-exporter/config"
+	"simple-exporter/prometheus"
+	"simple-exporter/rpc"
+	"simple-exporter/testutil"
+	"simple-exporter/types"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	var tests = []struct {
+		version string
+		want    [2]int
+		ok      bool
+	}{
+		{"v0.45.9", [2]int{0, 45}, true},
+		{"0.38.11", [2]int{0, 38}, true},
+		{"v0.47.5-ics", [2]int{0, 47}, true},
+		{"v1.0.0-rc1", [2]int{1, 0}, true},
+		{"", [2]int{}, false},
+		{"unknown", [2]int{}, false},
+	}
+	for _, test := range tests {
+		version, ok := parseVersion(test.version)
+		if version != test.want || ok != test.ok {
+			t.Errorf("parseVersion(%q) = %v, %t, want %v, %t", test.version, version, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestSDKBefore(t *testing.T) {
+	var tests = []struct {
+		versions *nodeVersions
+		want     bool
+	}{
+		{nil, false},
+		{&nodeVersions{sdk: ""}, false},
+		{&nodeVersions{sdk: "unknown"}, false},
+		{&nodeVersions{sdk: "v0.45.9"}, true},
+		{&nodeVersions{sdk: "v0.46.0"}, false},
+		{&nodeVersions{sdk: "v0.50.9"}, false},
+	}
+	for _, test := range tests {
+		if got := test.versions.sdkBefore(0, 46); got != test.want {
+			t.Errorf("sdkBefore(0, 46) of %+v = %t, want %t", test.versions, got, test.want)
+		}
+	}
+}
+
+func TestUpdateVersions(t *testing.T) {
+	for _, chain := range testutil.Chains {
+		t.Run(chain.Name, func(t *testing.T) {
+			var client = testutil.NewFixtureRPC(t, chain)
+			var monitor = newTestMonitor(abci.NewRPCClient(client))
+			nodeInfo, err := rpc.GetNodeInfo(client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = monitor.updateVersions(client, nodeInfo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if monitor.versions.cometBFT != chain.CometBFT || monitor.versions.sdk != chain.SDK || monitor.versions.appVersion == "" {
+				t.Errorf("got versions %+v, want CometBFT '%s' and Cosmos-Sdk '%s'", monitor.versions, chain.CometBFT, chain.SDK)
+			}
+			if len(monitor.versions.untested()) != 0 {
+				t.Errorf("got untested versions %v", monitor.versions.untested())
+			}
+		})
+	}
+}
+
+// hiddenPathsClient answers the hidden query paths like the chains without their module or service
+type hiddenPathsClient struct {
+	client abci.Client
+	hidden []string
+}
+
+func (c hiddenPathsClient) Query(ctx context.Context, path string, data []byte) (*types.ResultABCIQuery, error) {
+	for _, hidden := range c.hidden {
+		if path == hidden {
+			return &types.ResultABCIQuery{Response: types.ResponseQuery{Code: 6, Log: "unknown query path: unknown request", Codespace: "sdk"}}, nil
+		}
+	}
+	return c.client.Query(ctx, path, data)
+}
+
+func TestUnknownSDKVersion(t *testing.T) {
+	// a v0.45 chain without the tendermint service, its Cosmos-Sdk version is unknown
+	var chain = testutil.Chains[0]
+	var client = testutil.NewFixtureRPC(t, chain)
+	var monitor = newTestMonitor(hiddenPathsClient{client: abci.NewRPCClient(client), hidden: []string{"/cosmos.base.tendermint.v1beta1.Service/GetNodeInfo"}})
+	nodeInfo, err := rpc.GetNodeInfo(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = monitor.updateVersions(client, nodeInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if monitor.versions.sdk != "" || monitor.versions.sdkBefore(0, 46) {
+		t.Fatalf("got Cosmos-Sdk version '%s'", monitor.versions.sdk)
+	}
+
+	// the gov v1 module is tried first, falling back to the v1beta1 one
+	proposals, err := abci.GetVotingProposals(monitor.querier, monitor.versions.sdkBefore(0, 46))
+	if err != nil || len(proposals) != 1 {
+		t.Fatalf("got proposals %+v (%v)", proposals, err)
+	}
+	options, err := abci.GetGovVote(monitor.querier, proposals[0].ID, "cosmos1", monitor.versions.sdkBefore(0, 46))
+	if err != nil || len(options) != 1 {
+		t.Fatalf("got vote options %v (%v)", options, err)
+	}
+}
+
+// newTestMonitor creates a Monitor of a test target, querying the chain through the given client
+func newTestMonitor(querier abci.Client) *Monitor {
+	var monitor = NewMonitor(config.Target{Name: "test", ChainID: "test-1"}, &config.Config{}, prometheus.NewMetrics())
+	monitor.querier = querier
+	return monitor
+}
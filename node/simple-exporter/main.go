@@ -2,34 +2,65 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"log"
 	"os"
+	"simple-exporter/abci"
+	"simple-exporter/config"
 	"simple-exporter/core"
 	"simple-exporter/prometheus"
+	"simple-exporter/rpc"
 )
 
 var (
 	// Define string, int, and bool flags
-	nodeRpc = flag.String("node_rpc", "", "RPC endpoint of the wanted node (ex. https://rpc.cosmos.network:443)")
+	configFile = flag.String("config", "", "YAML or TOML configuration file (env "+config.EnvPrefix+"CONFIG_FILE)")
 )
 
 func main() {
-	var rpcAddr = ""
-	// get from the env
-	if os.Getenv("NODE_RPC") != "" {
-		rpcAddr = os.Getenv("NODE_RPC")
-	} else {
-		flag.Parse() // parse the command flags
-		// ensure valid node_rpc endpoint flags
-		if nodeRpc == nil || *nodeRpc == "" {
-			log.Fatal("Not valid -node_rpc flag.")
-		}
-		rpcAddr = *nodeRpc
+	// the "validate" subcommand only checks the configuration
+	var args = os.Args[1:]
+	var validateOnly = len(args) > 0 && args[0] == "validate"
+	if validateOnly {
+		args = args[1:]
 	}
 
-	log.Printf("Running RPC node: %s", rpcAddr)
+	config.RegisterFlags(flag.CommandLine)
+	_ = flag.CommandLine.Parse(args) // parse the command flags
+
+	var configPath = *configFile
+	if configPath == "" {
+		configPath = os.Getenv(config.EnvPrefix + "CONFIG_FILE")
+	}
+
+	// load the configuration, overridden by the env and the flags
+	cfg, err := config.Load(configPath, flag.CommandLine)
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		if validateOnly {
+			fmt.Println(fmt.Sprintf("Invalid configuration:\n%s", err.Error()))
+			os.Exit(1)
+		}
+		log.Fatal(fmt.Sprintf("Invalid configuration: %s", err.Error()))
+	}
+	if validateOnly {
+		fmt.Println(fmt.Sprintf("Configuration is valid (%d targets)", len(cfg.Targets)))
+		return
+	}
 
-	go prometheus.StartPrometheus(9090)
+	abci.QueryTimeout = cfg.QueryTimeout.Duration()
+	rpc.QueryTimeout = cfg.QueryTimeout.Duration()
+	if len(cfg.Targets) == 0 {
+		log.Println("No targets configured, serving only the /probe requests")
+	}
+	for _, target := range cfg.Targets {
+		log.Printf("Running target '%s' RPC node: %s", target.Name, target.RPC)
+		go core.NewMonitor(target, cfg, prometheus.DefaultMetrics).ListenWS()
+	}
 
-	core.ListenWS(rpcAddr)
+	prometheus.StartPrometheus(cfg.ListenPort, func(metrics *prometheus.Metrics, target string, validators []string) error {
+		return core.Probe(metrics, target, validators, cfg)
+	})
 }
@@ -2,199 +2,1053 @@ package prometheus
 
 import (
 	"github.com/cosmos/cosmos-sdk/types"
+	govV1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	slashingTypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	stakingTypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 	"github.com/prometheus/client_golang/prometheus"
+	"simple-exporter/abci"
+	"strconv"
+	"time"
 )
 
-// Define custom metrics for Node info
-var (
-	nodeInfo = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "node_info",
-			Help: "Node Info",
-		},
-		[]string{"network", "moniker", "id"},
-	)
-)
+// targetLabels are the labels identifying the target of all the metrics
+var targetLabels = []string{"chain_id", "target"}
 
-// Define custom metrics for Validator info
-var (
-	validatorInfo = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "validator_info",
-			Help: "Validator info",
-		},
-		[]string{"moniker", "valoper", "valcons"},
-	)
-)
+// validatorLabels are the labels identifying the validator of the metrics
+var validatorLabels = labelNames("moniker", "valcons")
 
-// Define custom metrics from Consensus
-var (
-	totalVotingPower = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "total_voting_power",
-		Help: "Total Validators Voting Power",
-	})
-	votingPower = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "validator_voting_power",
-		Help: "Validator Voting Power",
-	})
-)
+// labelNames returns the target labels followed by the given labels
+func labelNames(names ...string) []string {
+	return append(append([]string{}, targetLabels...), names...)
+}
 
-// Define custom metrics from Info
-var (
-	jailed = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "validator_jailed",
-		Help: "Validator Jailed Status",
-	})
-	rank = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "validator_rank",
-		Help: "Validator Rank",
-	})
-	minSelfDelegation = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "validator_min_self_delegation",
-		Help: "Validator Minimum Self Delegation",
-	})
-	delegatedTokens = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "validator_delegated_tokens",
-		Help: "Validator Delgated Tokens",
-	})
-	unbondingHeight = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "validator_unbonding_height",
-		Help: "Validator Unbonding Height",
-	})
-	commissionMaxChangeRate = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "validator_commission_max_change_rate",
-		Help: "Validator Max Change Rate",
-	})
-	commissionMaxRate = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "validator_commission_max_rate",
-		Help: "Validator Max Rate",
-	})
-	commissionRate = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "validator_commission_rate",
-		Help: "Validator Rate",
-	})
-)
+// Target exports the metrics of a monitored target
+type Target struct {
+	metrics *Metrics
+	chainID string
+	name    string
+}
 
-// Define custom metrics from Signing Info
-var (
-	tombstoned = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "validator_tombstoned",
-		Help: "Validator Tombstoned",
-	})
-	missedBlocks = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "validator_missed_blocks",
-		Help: "Validator Missed Blocks",
-	})
-)
+// NewTarget creates a Target exporting the metrics with its chain id and name labels
+func (m *Metrics) NewTarget(chainID string, name string) *Target {
+	return &Target{
+		metrics: m,
+		chainID: chainID,
+		name:    name,
+	}
+}
 
-// Define custom metric for balances
-var (
-	validatorCommission = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "validator_commission",
-			Help: "Validator Commission",
-		},
-		[]string{"denom"},
-	)
-	validatorRewards = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "validator_rewards",
-			Help: "Validator Rewards",
-		},
-		[]string{"denom"},
-	)
-)
+func (t *Target) labels(values ...string) []string {
+	return append([]string{t.chainID, t.name}, values...)
+}
+
+// partialLabels returns the target labels, used to delete all the target series of a metric
+func (t *Target) partialLabels() prometheus.Labels {
+	return prometheus.Labels{"chain_id": t.chainID, "target": t.name}
+}
+
+// Validator holds the labels values of a monitored validator
+type Validator struct {
+	Moniker string
+	Valcons string
+}
+
+func (v Validator) labels(values ...string) []string {
+	return append([]string{v.Moniker, v.Valcons}, values...)
+}
+
+// Metrics holds the exporter collectors, registered to a single Prometheus registry
+type Metrics struct {
+	// metrics for Node info
+	nodeInfo     *prometheus.GaugeVec
+	nodeVersions *prometheus.GaugeVec
+
+	// metrics from the node sync info
+	latestBlockHeight     *prometheus.GaugeVec
+	secondsSinceLastBlock *prometheus.GaugeVec
+	latestBlockTime       *prometheus.GaugeVec
+	catchingUp            *prometheus.GaugeVec
+	earliestBlockHeight   *prometheus.GaugeVec
+	blockStatus           *prometheus.GaugeVec
+	referenceBlockLag     *prometheus.GaugeVec
+	referenceTimeLag      *prometheus.GaugeVec
+	divergenceHeight      *prometheus.GaugeVec
+
+	// metrics from the node P2P peers
+	peers                 *prometheus.GaugeVec
+	peerInfo              *prometheus.GaugeVec
+	peerSendRate          *prometheus.GaugeVec
+	peerRecvRate          *prometheus.GaugeVec
+	peerConnectionSeconds *prometheus.GaugeVec
+	peerConnected         *prometheus.GaugeVec
+
+	// metrics for Validator info
+	validatorInfo *prometheus.GaugeVec
+
+	// metrics from Consensus
+	totalVotingPower    *prometheus.GaugeVec
+	votingPower         *prometheus.GaugeVec
+	votingPowerPercent  *prometheus.GaugeVec
+	nakamotoCoefficient *prometheus.GaugeVec
+	topValidatorsShare  *prometheus.GaugeVec
+
+	// metrics from Info
+	jailed                  *prometheus.GaugeVec
+	consumerOptedIn         *prometheus.GaugeVec
+	rank                    *prometheus.GaugeVec
+	minSelfDelegation       *prometheus.GaugeVec
+	delegatedTokens         *prometheus.GaugeVec
+	unbondingHeight         *prometheus.GaugeVec
+	commissionMaxChangeRate *prometheus.GaugeVec
+	commissionMaxRate       *prometheus.GaugeVec
+	commissionRate          *prometheus.GaugeVec
+
+	// metrics from Signing Info
+	tombstoned      *prometheus.GaugeVec
+	missedBlocks    *prometheus.GaugeVec
+	indexOffset     *prometheus.GaugeVec
+	jailedUntil     *prometheus.GaugeVec
+	blocksUntilJail *prometheus.GaugeVec
+
+	// metrics from Slashing params
+	signedBlocksWindow      *prometheus.GaugeVec
+	minSignedPerWindow      *prometheus.GaugeVec
+	downtimeJailDuration    *prometheus.GaugeVec
+	slashFractionDoubleSign *prometheus.GaugeVec
+	slashFractionDowntime   *prometheus.GaugeVec
+
+	// metrics from the blocks signatures
+	blockSigned        *prometheus.GaugeVec
+	missedBlocksStreak *prometheus.GaugeVec
+	signedBlocksTotal  *prometheus.CounterVec
+	missedBlocksTotal  *prometheus.CounterVec
+	uptime             *prometheus.GaugeVec
+
+	// metrics from the blocks proposers
+	proposerPriority       *prometheus.GaugeVec
+	proposalShare          *prometheus.GaugeVec
+	proposedBlocksTotal    *prometheus.CounterVec
+	expectedProposalsTotal *prometheus.CounterVec
+	missedProposals        *prometheus.GaugeVec
+
+	// metrics from the governance proposals
+	govProposalVotingEndTime *prometheus.GaugeVec
+	govVoted                 *prometheus.GaugeVec
+	govVote                  *prometheus.GaugeVec
+
+	// metrics from the upgrade plans
+	averageBlockTime           *prometheus.GaugeVec
+	upgradePlanHeight          *prometheus.GaugeVec
+	upgradePlanBlocksRemaining *prometheus.GaugeVec
+	upgradePlanTimeRemaining   *prometheus.GaugeVec
+	upgradeAppliedHeight       *prometheus.GaugeVec
+
+	// metrics from the cosmovisor folders
+	cosmovisorCurrent  *prometheus.GaugeVec
+	upgradeInfoHeight  *prometheus.GaugeVec
+	upgradeBinaryReady *prometheus.GaugeVec
+
+	// metrics from the active set
+	maxValidators         *prometheus.GaugeVec
+	lastActiveTokens      *prometheus.GaugeVec
+	bestInactiveTokens    *prometheus.GaugeVec
+	stakePosition         *prometheus.GaugeVec
+	activeSetMarginTokens *prometheus.GaugeVec
+
+	// metrics from the delegations
+	delegators             *prometheus.GaugeVec
+	selfDelegation         *prometheus.GaugeVec
+	selfDelegationMargin   *prometheus.GaugeVec
+	unbondingTokens        *prometheus.GaugeVec
+	topDelegation          *prometheus.GaugeVec
+	largeDelegationChanges *prometheus.CounterVec
+
+	// metrics for balances
+	validatorCommission     *prometheus.GaugeVec
+	validatorRewards        *prometheus.GaugeVec
+	accountBalance          *prometheus.GaugeVec
+	accountSpendableBalance *prometheus.GaugeVec
+}
+
+// DefaultMetrics are the metrics of the monitored targets, exposed on /metrics
+var DefaultMetrics = NewMetrics()
+
+// NewMetrics creates a new set of the exporter collectors
+func NewMetrics() *Metrics {
+	return &Metrics{
+		// metrics for Node info
+		nodeInfo: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "node_info",
+				Help: "Node Info",
+			},
+			labelNames("network", "moniker", "id"),
+		),
+		nodeVersions: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "node_versions_info",
+				Help: "Node software versions, the sdk_version is empty if not available",
+			},
+			labelNames("cometbft_version", "app_name", "app_version", "sdk_version"),
+		),
+
+		// metrics from the node sync info
+		latestBlockHeight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "node_latest_block_height",
+			Help: "Node latest block height",
+		}, targetLabels),
+		secondsSinceLastBlock: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "node_seconds_since_last_block",
+			Help: "Seconds elapsed since the node latest block time",
+		}, targetLabels),
+		latestBlockTime: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "node_latest_block_time_seconds",
+			Help: "Node latest block time (unix timestamp), the block age being time() - node_latest_block_time_seconds",
+		}, targetLabels),
+		catchingUp: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "node_catching_up",
+			Help: "Node catching up status",
+		}, targetLabels),
+		earliestBlockHeight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "node_earliest_block_height",
+			Help: "Node earliest retained block height, after the pruning",
+		}, targetLabels),
+		blockStatus: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "node_block_status",
+			Help: "Node blocks status (ok, stale, stuck or halted), comparing the node latest block with the reference nodes ones",
+		}, labelNames("status")),
+		referenceBlockLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "node_reference_block_lag",
+			Help: "Blocks of the node behind the best reference node, negative if ahead",
+		}, targetLabels),
+		referenceTimeLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "node_reference_block_time_lag_seconds",
+			Help: "Latest block time of the node behind the best reference node one",
+		}, targetLabels),
+		divergenceHeight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "node_divergence_height",
+			Help: "First height with a different block (block_hash) or application state (app_hash) on the node and the reference node",
+		}, labelNames("reference", "kind")),
+
+		// metrics from the node P2P peers
+		peers: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "node_peers",
+			Help: "Number of the node P2P peers by direction (inbound or outbound)",
+		}, labelNames("direction")),
+		peerInfo: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "node_peer_info",
+			Help: "Node P2P peer info",
+		}, labelNames("peer_id", "moniker", "remote_ip", "direction")),
+		peerSendRate: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "node_peer_send_rate_bytes",
+			Help: "Current bytes per second sent to the P2P peer",
+		}, labelNames("peer_id", "moniker")),
+		peerRecvRate: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "node_peer_recv_rate_bytes",
+			Help: "Current bytes per second received from the P2P peer",
+		}, labelNames("peer_id", "moniker")),
+		peerConnectionSeconds: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "node_peer_connection_duration_seconds",
+			Help: "Duration of the connection with the P2P peer",
+		}, labelNames("peer_id", "moniker")),
+		peerConnected: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "node_peer_connected",
+			Help: "Whether the configured persistent peer or sentry is connected to the node (0 or 1)",
+		}, labelNames("peer_id")),
+
+		// metrics for Validator info
+		validatorInfo: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "validator_info",
+				Help: "Validator info",
+			},
+			labelNames("moniker", "valoper", "valcons"),
+		),
+
+		// metrics from Consensus
+		totalVotingPower: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "total_voting_power",
+			Help: "Total Validators Voting Power",
+		}, targetLabels),
+		votingPower: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_voting_power",
+			Help: "Validator Voting Power",
+		}, validatorLabels),
+		votingPowerPercent: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_voting_power_percent",
+			Help: "Validator percentage of the Total Voting Power",
+		}, validatorLabels),
+		nakamotoCoefficient: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "nakamoto_coefficient",
+			Help: "Minimum number of validators holding more than 1/3 of the Total Voting Power",
+		}, targetLabels),
+		topValidatorsShare: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "top_validators_voting_power_share",
+			Help: "Share of the Total Voting Power held by the top 33% of the validators",
+		}, targetLabels),
+
+		// metrics from Info
+		jailed: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_jailed",
+			Help: "Validator Jailed Status",
+		}, validatorLabels),
+		consumerOptedIn: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_consumer_opted_in",
+			Help: "Validator opted in to validate the ICS consumer chain (partial set security)",
+		}, validatorLabels),
+		rank: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_rank",
+			Help: "Validator Rank by Voting Power in the active set",
+		}, validatorLabels),
+		minSelfDelegation: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_min_self_delegation",
+			Help: "Validator Minimum Self Delegation",
+		}, validatorLabels),
+		delegatedTokens: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_delegated_tokens",
+			Help: "Validator Delgated Tokens",
+		}, validatorLabels),
+		unbondingHeight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_unbonding_height",
+			Help: "Validator Unbonding Height",
+		}, validatorLabels),
+		commissionMaxChangeRate: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_commission_max_change_rate",
+			Help: "Validator Max Change Rate",
+		}, validatorLabels),
+		commissionMaxRate: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_commission_max_rate",
+			Help: "Validator Max Rate",
+		}, validatorLabels),
+		commissionRate: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_commission_rate",
+			Help: "Validator Rate",
+		}, validatorLabels),
+
+		// metrics from Signing Info
+		tombstoned: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_tombstoned",
+			Help: "Validator Tombstoned",
+		}, validatorLabels),
+		missedBlocks: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_missed_blocks",
+			Help: "Validator Missed Blocks",
+		}, validatorLabels),
+		indexOffset: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_index_offset",
+			Help: "Validator Signing Info Index Offset",
+		}, validatorLabels),
+		jailedUntil: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_jailed_until",
+			Help: "Validator Jailed Until (unix timestamp)",
+		}, validatorLabels),
+		blocksUntilJail: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_blocks_until_jail",
+			Help: "Validator further Missed Blocks allowed before being jailed",
+		}, validatorLabels),
+
+		// metrics from Slashing params
+		signedBlocksWindow: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "slashing_signed_blocks_window",
+			Help: "Slashing Signed Blocks Window",
+		}, targetLabels),
+		minSignedPerWindow: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "slashing_min_signed_per_window",
+			Help: "Slashing Min Signed Per Window",
+		}, targetLabels),
+		downtimeJailDuration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "slashing_downtime_jail_duration_seconds",
+			Help: "Slashing Downtime Jail Duration",
+		}, targetLabels),
+		slashFractionDoubleSign: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "slashing_slash_fraction_double_sign",
+			Help: "Slashing Slash Fraction Double Sign",
+		}, targetLabels),
+		slashFractionDowntime: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "slashing_slash_fraction_downtime",
+			Help: "Slashing Slash Fraction Downtime",
+		}, targetLabels),
+
+		// metrics from the blocks signatures
+		blockSigned: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_last_block_signed",
+			Help: "Validator signed the last processed block",
+		}, validatorLabels),
+		missedBlocksStreak: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_missed_blocks_streak",
+			Help: "Validator consecutive missed blocks",
+		}, validatorLabels),
+		signedBlocksTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "validator_signed_blocks_total",
+			Help: "Validator signed blocks since the exporter start",
+		}, validatorLabels),
+		missedBlocksTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "validator_missed_blocks_total",
+			Help: "Validator missed blocks since the exporter start",
+		}, validatorLabels),
+		uptime: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_uptime",
+			Help: "Validator ratio of signed blocks over the recent blocks window",
+		}, validatorLabels),
+
+		// metrics from the blocks proposers
+		proposerPriority: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_proposer_priority",
+			Help: "Validator current proposer priority",
+		}, validatorLabels),
+		proposalShare: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_proposal_share",
+			Help: "Validator expected share of the proposed blocks, from its voting power",
+		}, validatorLabels),
+		proposedBlocksTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "validator_proposed_blocks_total",
+			Help: "Validator proposed blocks since the exporter start",
+		}, validatorLabels),
+		expectedProposalsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "validator_expected_proposals_total",
+			Help: "Validator expected proposed blocks from its voting power share since the exporter start",
+		}, validatorLabels),
+		missedProposals: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_missed_proposals",
+			Help: "Validator blocks expected to be proposed but proposed by another validator over the recent blocks window",
+		}, validatorLabels),
 
-func UpdateNodeInfo(isOnline bool, network string, moniker string, id string) {
+		// metrics from the governance proposals
+		govProposalVotingEndTime: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "gov_proposal_voting_end_time",
+			Help: "Governance proposal in voting period end time",
+		}, labelNames("proposal_id")),
+		govVoted: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_gov_voted",
+			Help: "Validator operator account voted the governance proposal in voting period",
+		}, labelNames("moniker", "valcons", "proposal_id")),
+		govVote: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_gov_vote",
+			Help: "Validator operator account vote options of the governance proposal in voting period",
+		}, labelNames("moniker", "valcons", "proposal_id", "option")),
+
+		// metrics from the upgrade plans
+		averageBlockTime: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "average_block_time_seconds",
+			Help: "Average block time over the recent blocks",
+		}, targetLabels),
+		upgradePlanHeight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "upgrade_plan_height",
+			Help: "Scheduled upgrade plan height",
+		}, labelNames("name")),
+		upgradePlanBlocksRemaining: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "upgrade_plan_blocks_remaining",
+			Help: "Blocks remaining before the scheduled upgrade plan",
+		}, labelNames("name")),
+		upgradePlanTimeRemaining: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "upgrade_plan_time_remaining_seconds",
+			Help: "Estimated time remaining before the scheduled upgrade plan, from the average block time",
+		}, labelNames("name")),
+		upgradeAppliedHeight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "upgrade_applied_height",
+			Help: "Height at which the last upgrade plan was applied",
+		}, labelNames("name")),
+
+		// metrics from the cosmovisor folders
+		cosmovisorCurrent: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cosmovisor_current_upgrade",
+			Help: "Upgrade pointed by the cosmovisor current symlink",
+		}, labelNames("name")),
+		upgradeInfoHeight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cosmovisor_upgrade_info_height",
+			Help: "Height of the upgrade the node halted for, from the upgrade-info.json file",
+		}, labelNames("name")),
+		upgradeBinaryReady: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cosmovisor_upgrade_binary_ready",
+			Help: "Binary of the scheduled upgrade plan is staged in the cosmovisor folders and executable",
+		}, labelNames("name")),
+
+		// metrics from the active set
+		maxValidators: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "staking_max_validators",
+			Help: "Staking Max Validators in the active set",
+		}, targetLabels),
+		lastActiveTokens: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "active_set_last_tokens",
+			Help: "Tokens of the last validator in the active set",
+		}, targetLabels),
+		bestInactiveTokens: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "active_set_best_inactive_tokens",
+			Help: "Tokens of the best not jailed validator out of the active set",
+		}, targetLabels),
+		stakePosition: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_stake_position",
+			Help: "Validator position by tokens among the not jailed validators",
+		}, validatorLabels),
+		activeSetMarginTokens: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_active_set_margin_tokens",
+			Help: "Validator tokens above the last validator in the active set, negative if out of the active set",
+		}, validatorLabels),
+
+		// metrics from the delegations
+		delegators: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_delegators",
+			Help: "Validator number of delegators",
+		}, validatorLabels),
+		selfDelegation: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_self_delegation_tokens",
+			Help: "Validator tokens delegated by the operator account",
+		}, validatorLabels),
+		selfDelegationMargin: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_self_delegation_margin_tokens",
+			Help: "Validator self delegation tokens above the min self delegation, jailed if negative",
+		}, validatorLabels),
+		unbondingTokens: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_unbonding_tokens",
+			Help: "Validator tokens in unbonding",
+		}, validatorLabels),
+		topDelegation: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "validator_top_delegation_tokens",
+			Help: "Validator largest delegations tokens",
+		}, labelNames("moniker", "valcons", "position", "delegator")),
+		largeDelegationChanges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "validator_large_delegation_changes_total",
+			Help: "Validator delegations changed more than the configured threshold since the exporter start",
+		}, labelNames("moniker", "valcons", "direction")),
+
+		// metrics for balances
+		validatorCommission: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "validator_commission",
+				Help: "Validator Commission",
+			},
+			labelNames("moniker", "valcons", "denom"),
+		),
+		validatorRewards: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "validator_rewards",
+				Help: "Validator Rewards",
+			},
+			labelNames("moniker", "valcons", "denom"),
+		),
+		accountBalance: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "account_balance",
+			Help: "Account balance",
+		}, labelNames("address", "alias", "denom")),
+		accountSpendableBalance: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "account_spendable_balance",
+			Help: "Account spendable balance, without the vesting coins",
+		}, labelNames("address", "alias", "denom")),
+	}
+}
+
+// Register registers all the collectors to the registerer
+func (m *Metrics) Register(registerer prometheus.Registerer) {
+	registerer.MustRegister(m.nodeInfo)
+	registerer.MustRegister(m.nodeVersions)
+	registerer.MustRegister(m.latestBlockHeight)
+	registerer.MustRegister(m.secondsSinceLastBlock)
+	registerer.MustRegister(m.latestBlockTime)
+	registerer.MustRegister(m.catchingUp)
+	registerer.MustRegister(m.earliestBlockHeight)
+	registerer.MustRegister(m.blockStatus)
+	registerer.MustRegister(m.referenceBlockLag)
+	registerer.MustRegister(m.referenceTimeLag)
+	registerer.MustRegister(m.divergenceHeight)
+	registerer.MustRegister(m.peers)
+	registerer.MustRegister(m.peerInfo)
+	registerer.MustRegister(m.peerSendRate)
+	registerer.MustRegister(m.peerRecvRate)
+	registerer.MustRegister(m.peerConnectionSeconds)
+	registerer.MustRegister(m.peerConnected)
+	registerer.MustRegister(m.validatorInfo)
+	registerer.MustRegister(m.totalVotingPower)
+	registerer.MustRegister(m.votingPower)
+	registerer.MustRegister(m.votingPowerPercent)
+	registerer.MustRegister(m.nakamotoCoefficient)
+	registerer.MustRegister(m.topValidatorsShare)
+	registerer.MustRegister(m.jailed)
+	registerer.MustRegister(m.consumerOptedIn)
+	registerer.MustRegister(m.rank)
+	registerer.MustRegister(m.minSelfDelegation)
+	registerer.MustRegister(m.delegatedTokens)
+	registerer.MustRegister(m.unbondingHeight)
+	registerer.MustRegister(m.commissionMaxChangeRate)
+	registerer.MustRegister(m.commissionMaxRate)
+	registerer.MustRegister(m.commissionRate)
+	registerer.MustRegister(m.tombstoned)
+	registerer.MustRegister(m.missedBlocks)
+	registerer.MustRegister(m.indexOffset)
+	registerer.MustRegister(m.jailedUntil)
+	registerer.MustRegister(m.blocksUntilJail)
+	registerer.MustRegister(m.signedBlocksWindow)
+	registerer.MustRegister(m.minSignedPerWindow)
+	registerer.MustRegister(m.downtimeJailDuration)
+	registerer.MustRegister(m.slashFractionDoubleSign)
+	registerer.MustRegister(m.slashFractionDowntime)
+	registerer.MustRegister(m.blockSigned)
+	registerer.MustRegister(m.missedBlocksStreak)
+	registerer.MustRegister(m.signedBlocksTotal)
+	registerer.MustRegister(m.missedBlocksTotal)
+	registerer.MustRegister(m.uptime)
+	registerer.MustRegister(m.proposerPriority)
+	registerer.MustRegister(m.proposalShare)
+	registerer.MustRegister(m.proposedBlocksTotal)
+	registerer.MustRegister(m.expectedProposalsTotal)
+	registerer.MustRegister(m.missedProposals)
+	registerer.MustRegister(m.govProposalVotingEndTime)
+	registerer.MustRegister(m.govVoted)
+	registerer.MustRegister(m.govVote)
+	registerer.MustRegister(m.averageBlockTime)
+	registerer.MustRegister(m.upgradePlanHeight)
+	registerer.MustRegister(m.upgradePlanBlocksRemaining)
+	registerer.MustRegister(m.upgradePlanTimeRemaining)
+	registerer.MustRegister(m.upgradeAppliedHeight)
+	registerer.MustRegister(m.cosmovisorCurrent)
+	registerer.MustRegister(m.upgradeInfoHeight)
+	registerer.MustRegister(m.upgradeBinaryReady)
+	registerer.MustRegister(m.maxValidators)
+	registerer.MustRegister(m.lastActiveTokens)
+	registerer.MustRegister(m.bestInactiveTokens)
+	registerer.MustRegister(m.stakePosition)
+	registerer.MustRegister(m.activeSetMarginTokens)
+	registerer.MustRegister(m.delegators)
+	registerer.MustRegister(m.selfDelegation)
+	registerer.MustRegister(m.selfDelegationMargin)
+	registerer.MustRegister(m.unbondingTokens)
+	registerer.MustRegister(m.topDelegation)
+	registerer.MustRegister(m.largeDelegationChanges)
+	registerer.MustRegister(m.validatorCommission)
+	registerer.MustRegister(m.validatorRewards)
+	registerer.MustRegister(m.accountBalance)
+	registerer.MustRegister(m.accountSpendableBalance)
+}
+
+func (t *Target) UpdateNodeInfo(isOnline bool, network string, moniker string, id string) {
 	var onlineValue = 0
 	if isOnline {
 		onlineValue = 1
 	}
-	nodeInfo.WithLabelValues(network, moniker, id).Set(float64(onlineValue))
+	t.metrics.nodeInfo.WithLabelValues(t.labels(network, moniker, id)...).Set(float64(onlineValue))
+}
+
+func (t *Target) DeleteNodeInfo(network string, moniker string, id string) {
+	t.metrics.nodeInfo.DeleteLabelValues(t.labels(network, moniker, id)...)
+}
+
+func (t *Target) UpdateNodeVersions(cometBFTVersion string, appName string, appVersion string, sdkVersion string) {
+	t.metrics.nodeVersions.WithLabelValues(t.labels(cometBFTVersion, appName, appVersion, sdkVersion)...).Set(1)
+}
+
+func (t *Target) DeleteNodeVersions(cometBFTVersion string, appName string, appVersion string, sdkVersion string) {
+	t.metrics.nodeVersions.DeleteLabelValues(t.labels(cometBFTVersion, appName, appVersion, sdkVersion)...)
+}
+
+func (t *Target) UpdateSyncInfo(latestHeight int64, latestBlockTime time.Time, catchingUp bool, earliestHeight int64) {
+	t.metrics.latestBlockHeight.WithLabelValues(t.labels()...).Set(float64(latestHeight))
+	t.metrics.latestBlockTime.WithLabelValues(t.labels()...).Set(float64(latestBlockTime.UnixMilli()) / 1000)
+	t.UpdateSecondsSinceLastBlock(latestBlockTime)
+	if catchingUp {
+		t.metrics.catchingUp.WithLabelValues(t.labels()...).Set(1)
+	} else {
+		t.metrics.catchingUp.WithLabelValues(t.labels()...).Set(0)
+	}
+	t.metrics.earliestBlockHeight.WithLabelValues(t.labels()...).Set(float64(earliestHeight))
+}
+
+// UpdateSecondsSinceLastBlock sets the age of the node latest block, also when the node status is not available
+func (t *Target) UpdateSecondsSinceLastBlock(latestBlockTime time.Time) {
+	t.metrics.secondsSinceLastBlock.WithLabelValues(t.labels()...).Set(time.Since(latestBlockTime).Seconds())
+}
+
+func (t *Target) UpdateReferenceLag(blocks int64, seconds float64) {
+	t.metrics.referenceBlockLag.WithLabelValues(t.labels()...).Set(float64(blocks))
+	t.metrics.referenceTimeLag.WithLabelValues(t.labels()...).Set(seconds)
+}
+
+// DeleteReferenceLag deletes the lag metrics while no reference node is available
+func (t *Target) DeleteReferenceLag() {
+	t.metrics.referenceBlockLag.DeleteLabelValues(t.labels()...)
+	t.metrics.referenceTimeLag.DeleteLabelValues(t.labels()...)
+}
+
+// UpdateDivergence sets the first divergent height with the reference node, resetting the previous divergence kind
+func (t *Target) UpdateDivergence(reference string, kind string, height int64) {
+	t.DeleteDivergence(reference)
+	t.metrics.divergenceHeight.WithLabelValues(t.labels(reference, kind)...).Set(float64(height))
+}
+
+// DeleteDivergence deletes the divergence with the reference node, once the nodes agree again
+func (t *Target) DeleteDivergence(reference string) {
+	var partialLabels = t.partialLabels()
+	partialLabels["reference"] = reference
+	t.metrics.divergenceHeight.DeletePartialMatch(partialLabels)
+}
+
+// UpdatePeers sets the number of peers by direction, removing the disconnected peers metrics
+func (t *Target) UpdatePeers(inbound int, outbound int) {
+	t.metrics.peers.WithLabelValues(t.labels("inbound")...).Set(float64(inbound))
+	t.metrics.peers.WithLabelValues(t.labels("outbound")...).Set(float64(outbound))
+	t.metrics.peerInfo.DeletePartialMatch(t.partialLabels())
+	t.metrics.peerSendRate.DeletePartialMatch(t.partialLabels())
+	t.metrics.peerRecvRate.DeletePartialMatch(t.partialLabels())
+	t.metrics.peerConnectionSeconds.DeletePartialMatch(t.partialLabels())
+}
+
+func (t *Target) UpdatePeer(id string, moniker string, remoteIP string, isOutbound bool, sendRate int64, recvRate int64, duration time.Duration) {
+	var direction = "inbound"
+	if isOutbound {
+		direction = "outbound"
+	}
+	t.metrics.peerInfo.WithLabelValues(t.labels(id, moniker, remoteIP, direction)...).Set(1)
+	t.metrics.peerSendRate.WithLabelValues(t.labels(id, moniker)...).Set(float64(sendRate))
+	t.metrics.peerRecvRate.WithLabelValues(t.labels(id, moniker)...).Set(float64(recvRate))
+	t.metrics.peerConnectionSeconds.WithLabelValues(t.labels(id, moniker)...).Set(duration.Seconds())
+}
+
+func (t *Target) UpdatePeerConnected(id string, isConnected bool) {
+	var connectedValue = 0
+	if isConnected {
+		connectedValue = 1
+	}
+	t.metrics.peerConnected.WithLabelValues(t.labels(id)...).Set(float64(connectedValue))
 }
 
-func DeleteNodeInfo(network string, moniker string, id string) {
-	nodeInfo.DeleteLabelValues(network, moniker, id)
+// DeletePeers deletes the peers metrics while the node P2P info is not available
+func (t *Target) DeletePeers() {
+	t.metrics.peers.DeletePartialMatch(t.partialLabels())
+	t.metrics.peerInfo.DeletePartialMatch(t.partialLabels())
+	t.metrics.peerSendRate.DeletePartialMatch(t.partialLabels())
+	t.metrics.peerRecvRate.DeletePartialMatch(t.partialLabels())
+	t.metrics.peerConnectionSeconds.DeletePartialMatch(t.partialLabels())
+	t.metrics.peerConnected.DeletePartialMatch(t.partialLabels())
 }
 
-func UpdateValidatorInfo(isOnline bool, moniker string, valoper string, valcons string) {
+// node blocks statuses
+const (
+	BlockStatusOK     = "ok"     // the node receives new blocks
+	BlockStatusStale  = "stale"  // the node doesn't receive new blocks, no reference node available to tell why
+	BlockStatusStuck  = "stuck"  // the node doesn't receive new blocks, while the reference nodes do
+	BlockStatusHalted = "halted" // neither the node nor the reference nodes receive new blocks, the chain is halted
+)
+
+// UpdateBlockStatus sets the current node blocks status, resetting the other ones
+func (t *Target) UpdateBlockStatus(status string) {
+	for _, value := range []string{BlockStatusOK, BlockStatusStale, BlockStatusStuck, BlockStatusHalted} {
+		if value == status {
+			t.metrics.blockStatus.WithLabelValues(t.labels(value)...).Set(1)
+		} else {
+			t.metrics.blockStatus.WithLabelValues(t.labels(value)...).Set(0)
+		}
+	}
+}
+
+func (t *Target) UpdateValidatorInfo(isOnline bool, moniker string, valoper string, valcons string) {
 	var onlineValue = 0
 	if isOnline {
 		onlineValue = 1
 	}
-	validatorInfo.WithLabelValues(moniker, valoper, valcons).Set(float64(onlineValue))
+	t.metrics.validatorInfo.WithLabelValues(t.labels(moniker, valoper, valcons)...).Set(float64(onlineValue))
+}
+
+func (t *Target) UpdateRank(validator Validator, value int) {
+	t.metrics.rank.WithLabelValues(t.labels(validator.labels()...)...).Set(float64(value))
+}
+
+func (t *Target) DeleteRank(validator Validator) {
+	t.metrics.rank.DeleteLabelValues(t.labels(validator.labels()...)...)
 }
 
-func UpdateRank(value int) {
-	rank.Set(float64(value))
+func (t *Target) UpdateVotingPowerPercent(validator Validator, value float64) {
+	t.metrics.votingPowerPercent.WithLabelValues(t.labels(validator.labels()...)...).Set(value)
 }
 
-func UpdateTotalVotingPower(value uint64) {
-	totalVotingPower.Set(float64(value))
+func (t *Target) UpdatePowerDistribution(nakamotoCoefficient int, topValidatorsShare float64) {
+	t.metrics.nakamotoCoefficient.WithLabelValues(t.labels()...).Set(float64(nakamotoCoefficient))
+	t.metrics.topValidatorsShare.WithLabelValues(t.labels()...).Set(topValidatorsShare)
 }
 
-func UpdateVotingPower(value uint64) {
-	votingPower.Set(float64(value))
+func (t *Target) UpdateTotalVotingPower(value uint64) {
+	t.metrics.totalVotingPower.WithLabelValues(t.labels()...).Set(float64(value))
 }
 
-func UpdateJailed(isJailed bool) {
+func (t *Target) UpdateVotingPower(validator Validator, value uint64) {
+	t.metrics.votingPower.WithLabelValues(t.labels(validator.labels()...)...).Set(float64(value))
+}
+
+func (t *Target) UpdateJailed(validator Validator, isJailed bool) {
 	if isJailed {
-		jailed.Set(1)
+		t.metrics.jailed.WithLabelValues(t.labels(validator.labels()...)...).Set(1)
+	} else {
+		t.metrics.jailed.WithLabelValues(t.labels(validator.labels()...)...).Set(0)
+	}
+}
+
+func (t *Target) UpdateConsumerOptedIn(validator Validator, isOptedIn bool) {
+	if isOptedIn {
+		t.metrics.consumerOptedIn.WithLabelValues(t.labels(validator.labels()...)...).Set(1)
 	} else {
-		jailed.Set(0)
+		t.metrics.consumerOptedIn.WithLabelValues(t.labels(validator.labels()...)...).Set(0)
 	}
 }
 
-func UpdateMinSelfDelegation(value uint64) {
-	minSelfDelegation.Set(float64(value))
+func (t *Target) UpdateMinSelfDelegation(validator Validator, value types.Int) {
+	t.metrics.minSelfDelegation.WithLabelValues(t.labels(validator.labels()...)...).Set(intToFloat(value))
 }
 
-func UpdateDelegatedTokens(value uint64) {
-	delegatedTokens.Set(float64(value))
+func (t *Target) UpdateDelegatedTokens(validator Validator, value types.Int) {
+	t.metrics.delegatedTokens.WithLabelValues(t.labels(validator.labels()...)...).Set(intToFloat(value))
 }
 
-func UpdateUnbondingHeight(value int64) {
-	unbondingHeight.Set(float64(value))
+func (t *Target) UpdateUnbondingHeight(validator Validator, value int64) {
+	t.metrics.unbondingHeight.WithLabelValues(t.labels(validator.labels()...)...).Set(float64(value))
 }
 
-func UpdateTombstoned(isTombstoned bool) {
+func (t *Target) UpdateTombstoned(validator Validator, isTombstoned bool) {
 	if isTombstoned {
-		tombstoned.Set(1)
+		t.metrics.tombstoned.WithLabelValues(t.labels(validator.labels()...)...).Set(1)
+	} else {
+		t.metrics.tombstoned.WithLabelValues(t.labels(validator.labels()...)...).Set(0)
+	}
+}
+
+func (t *Target) UpdateMissedBlocks(validator Validator, value int64) {
+	t.metrics.missedBlocks.WithLabelValues(t.labels(validator.labels()...)...).Set(float64(value))
+}
+
+func (t *Target) UpdateIndexOffset(validator Validator, value int64) {
+	t.metrics.indexOffset.WithLabelValues(t.labels(validator.labels()...)...).Set(float64(value))
+}
+
+func (t *Target) UpdateJailedUntil(validator Validator, value time.Time) {
+	t.metrics.jailedUntil.WithLabelValues(t.labels(validator.labels()...)...).Set(float64(value.Unix()))
+}
+
+func (t *Target) UpdateBlocksUntilJail(validator Validator, value int64) {
+	t.metrics.blocksUntilJail.WithLabelValues(t.labels(validator.labels()...)...).Set(float64(value))
+}
+
+func (t *Target) UpdateSlashingParams(params *slashingTypes.Params) {
+	t.metrics.signedBlocksWindow.WithLabelValues(t.labels()...).Set(float64(params.SignedBlocksWindow))
+	t.metrics.minSignedPerWindow.WithLabelValues(t.labels()...).Set(params.MinSignedPerWindow.MustFloat64())
+	t.metrics.downtimeJailDuration.WithLabelValues(t.labels()...).Set(params.DowntimeJailDuration.Seconds())
+	t.metrics.slashFractionDoubleSign.WithLabelValues(t.labels()...).Set(params.SlashFractionDoubleSign.MustFloat64())
+	t.metrics.slashFractionDowntime.WithLabelValues(t.labels()...).Set(params.SlashFractionDowntime.MustFloat64())
+}
+
+func (t *Target) UpdateBlockSigned(validator Validator, isSigned bool) {
+	if isSigned {
+		t.metrics.blockSigned.WithLabelValues(t.labels(validator.labels()...)...).Set(1)
+		t.metrics.signedBlocksTotal.WithLabelValues(t.labels(validator.labels()...)...).Inc()
 	} else {
-		tombstoned.Set(0)
+		t.metrics.blockSigned.WithLabelValues(t.labels(validator.labels()...)...).Set(0)
+		t.metrics.missedBlocksTotal.WithLabelValues(t.labels(validator.labels()...)...).Inc()
 	}
 }
 
-func UpdateMissedBlocks(value int64) {
-	missedBlocks.Set(float64(value))
+func (t *Target) UpdateMissedBlocksStreak(validator Validator, value int) {
+	t.metrics.missedBlocksStreak.WithLabelValues(t.labels(validator.labels()...)...).Set(float64(value))
 }
 
-func UpdateCommissionRate(value float64) {
-	commissionRate.Set(value)
+func (t *Target) UpdateUptime(validator Validator, value float64) {
+	t.metrics.uptime.WithLabelValues(t.labels(validator.labels()...)...).Set(value)
 }
 
-func UpdateCommissionMaxRate(value float64) {
-	commissionMaxRate.Set(value)
+func (t *Target) UpdateProposerPriority(validator Validator, value int64) {
+	t.metrics.proposerPriority.WithLabelValues(t.labels(validator.labels()...)...).Set(float64(value))
 }
 
-func UpdateCommissionMaxChangeRate(value float64) {
-	commissionMaxChangeRate.Set(value)
+func (t *Target) UpdateProposalShare(validator Validator, value float64) {
+	t.metrics.proposalShare.WithLabelValues(t.labels(validator.labels()...)...).Set(value)
 }
 
-func UpdateValidatorCommission(coins *types.DecCoins) {
+func (t *Target) UpdateBlockProposed(validator Validator, isProposed bool, expectedShare float64) {
+	if isProposed {
+		t.metrics.proposedBlocksTotal.WithLabelValues(t.labels(validator.labels()...)...).Inc()
+	} else {
+		// initialize the counter, so that the missing proposals are visible
+		t.metrics.proposedBlocksTotal.WithLabelValues(t.labels(validator.labels()...)...).Add(0)
+	}
+	t.metrics.expectedProposalsTotal.WithLabelValues(t.labels(validator.labels()...)...).Add(expectedShare)
+}
+
+func (t *Target) UpdateMissedProposals(validator Validator, value int) {
+	t.metrics.missedProposals.WithLabelValues(t.labels(validator.labels()...)...).Set(float64(value))
+}
+
+// UpdateGovProposals sets the proposals in voting period, removing the ended ones
+func (t *Target) UpdateGovProposals(proposals []abci.GovProposal) {
+	t.metrics.govProposalVotingEndTime.DeletePartialMatch(t.partialLabels())
+	for _, proposal := range proposals {
+		t.metrics.govProposalVotingEndTime.WithLabelValues(t.labels(strconv.FormatUint(proposal.ID, 10))...).Set(float64(proposal.VotingEndTime.Unix()))
+	}
+}
+
+// UpdateGovVotes sets the validator votes of the proposals in voting period, by proposal id
+func (t *Target) UpdateGovVotes(validator Validator, votes map[uint64][]govV1.VoteOption) {
+	var partialLabels = t.partialLabels()
+	partialLabels["valcons"] = validator.Valcons
+	t.metrics.govVoted.DeletePartialMatch(partialLabels)
+	t.metrics.govVote.DeletePartialMatch(partialLabels)
+
+	for proposalID, options := range votes {
+		var id = strconv.FormatUint(proposalID, 10)
+		if len(options) == 0 {
+			t.metrics.govVoted.WithLabelValues(t.labels(validator.labels(id)...)...).Set(0)
+			continue
+		}
+		t.metrics.govVoted.WithLabelValues(t.labels(validator.labels(id)...)...).Set(1)
+		for _, option := range options {
+			t.metrics.govVote.WithLabelValues(t.labels(validator.labels(id, option.String())...)...).Set(1)
+		}
+	}
+}
+
+func (t *Target) UpdateAverageBlockTime(value time.Duration) {
+	t.metrics.averageBlockTime.WithLabelValues(t.labels()...).Set(value.Seconds())
+}
+
+// UpdateUpgradePlan sets the scheduled upgrade plan, removing the previous one
+func (t *Target) UpdateUpgradePlan(name string, height int64, blocksRemaining int64, timeRemaining time.Duration) {
+	t.DeleteUpgradePlan()
+	t.metrics.upgradePlanHeight.WithLabelValues(t.labels(name)...).Set(float64(height))
+	t.metrics.upgradePlanBlocksRemaining.WithLabelValues(t.labels(name)...).Set(float64(blocksRemaining))
+	t.metrics.upgradePlanTimeRemaining.WithLabelValues(t.labels(name)...).Set(timeRemaining.Seconds())
+}
+
+func (t *Target) DeleteUpgradePlan() {
+	t.metrics.upgradePlanHeight.DeletePartialMatch(t.partialLabels())
+	t.metrics.upgradePlanBlocksRemaining.DeletePartialMatch(t.partialLabels())
+	t.metrics.upgradePlanTimeRemaining.DeletePartialMatch(t.partialLabels())
+}
+
+// UpdateUpgradeApplied sets the last applied upgrade plan, removing the previous one
+func (t *Target) UpdateUpgradeApplied(name string, height int64) {
+	t.metrics.upgradeAppliedHeight.DeletePartialMatch(t.partialLabels())
+	t.metrics.upgradeAppliedHeight.WithLabelValues(t.labels(name)...).Set(float64(height))
+}
+
+func (t *Target) UpdateCosmovisorCurrent(name string) {
+	t.DeleteCosmovisorCurrent()
+	t.metrics.cosmovisorCurrent.WithLabelValues(t.labels(name)...).Set(1)
+}
+
+func (t *Target) DeleteCosmovisorCurrent() {
+	t.metrics.cosmovisorCurrent.DeletePartialMatch(t.partialLabels())
+}
+
+func (t *Target) UpdateUpgradeInfo(name string, height int64) {
+	t.DeleteUpgradeInfo()
+	t.metrics.upgradeInfoHeight.WithLabelValues(t.labels(name)...).Set(float64(height))
+}
+
+func (t *Target) DeleteUpgradeInfo() {
+	t.metrics.upgradeInfoHeight.DeletePartialMatch(t.partialLabels())
+}
+
+func (t *Target) UpdateUpgradeBinaryReady(name string, isReady bool) {
+	t.DeleteUpgradeBinaryReady()
+	if isReady {
+		t.metrics.upgradeBinaryReady.WithLabelValues(t.labels(name)...).Set(1)
+	} else {
+		t.metrics.upgradeBinaryReady.WithLabelValues(t.labels(name)...).Set(0)
+	}
+}
+
+func (t *Target) DeleteUpgradeBinaryReady() {
+	t.metrics.upgradeBinaryReady.DeletePartialMatch(t.partialLabels())
+}
+
+func (t *Target) UpdateActiveSet(maxValidators uint32, lastActiveTokens types.Int, bestInactiveTokens types.Int) {
+	t.metrics.maxValidators.WithLabelValues(t.labels()...).Set(float64(maxValidators))
+	t.metrics.lastActiveTokens.WithLabelValues(t.labels()...).Set(intToFloat(lastActiveTokens))
+	t.metrics.bestInactiveTokens.WithLabelValues(t.labels()...).Set(intToFloat(bestInactiveTokens))
+}
+
+func (t *Target) UpdateStakePosition(validator Validator, position int, marginTokens types.Int) {
+	t.metrics.stakePosition.WithLabelValues(t.labels(validator.labels()...)...).Set(float64(position))
+	t.metrics.activeSetMarginTokens.WithLabelValues(t.labels(validator.labels()...)...).Set(intToFloat(marginTokens))
+}
+
+func (t *Target) DeleteStakePosition(validator Validator) {
+	t.metrics.stakePosition.DeleteLabelValues(t.labels(validator.labels()...)...)
+	t.metrics.activeSetMarginTokens.DeleteLabelValues(t.labels(validator.labels()...)...)
+}
+
+func (t *Target) UpdateSelfDelegation(validator Validator, selfDelegation types.Int, margin types.Int) {
+	t.metrics.selfDelegation.WithLabelValues(t.labels(validator.labels()...)...).Set(intToFloat(selfDelegation))
+	t.metrics.selfDelegationMargin.WithLabelValues(t.labels(validator.labels()...)...).Set(intToFloat(margin))
+}
+
+func (t *Target) UpdateDelegations(validator Validator, delegators int, unbondingTokens types.Int) {
+	t.metrics.delegators.WithLabelValues(t.labels(validator.labels()...)...).Set(float64(delegators))
+	t.metrics.unbondingTokens.WithLabelValues(t.labels(validator.labels()...)...).Set(intToFloat(unbondingTokens))
+}
+
+// UpdateTopDelegations sets the largest delegations, sorted by tokens, removing the previous ones
+func (t *Target) UpdateTopDelegations(validator Validator, delegations stakingTypes.DelegationResponses) {
+	var partialLabels = t.partialLabels()
+	partialLabels["valcons"] = validator.Valcons
+	t.metrics.topDelegation.DeletePartialMatch(partialLabels)
+	for i, delegation := range delegations {
+		t.metrics.topDelegation.WithLabelValues(t.labels(validator.labels(strconv.Itoa(i+1), delegation.Delegation.DelegatorAddress)...)...).Set(intToFloat(delegation.Balance.Amount))
+	}
+}
+
+func (t *Target) IncLargeDelegationChange(validator Validator, isDelegation bool) {
+	if isDelegation {
+		t.metrics.largeDelegationChanges.WithLabelValues(t.labels(validator.labels("delegation")...)...).Inc()
+	} else {
+		t.metrics.largeDelegationChanges.WithLabelValues(t.labels(validator.labels("undelegation")...)...).Inc()
+	}
+}
+
+func (t *Target) UpdateCommissionRate(validator Validator, value float64) {
+	t.metrics.commissionRate.WithLabelValues(t.labels(validator.labels()...)...).Set(value)
+}
+
+func (t *Target) UpdateCommissionMaxRate(validator Validator, value float64) {
+	t.metrics.commissionMaxRate.WithLabelValues(t.labels(validator.labels()...)...).Set(value)
+}
+
+func (t *Target) UpdateCommissionMaxChangeRate(validator Validator, value float64) {
+	t.metrics.commissionMaxChangeRate.WithLabelValues(t.labels(validator.labels()...)...).Set(value)
+}
+
+func (t *Target) UpdateValidatorCommission(validator Validator, coins *types.DecCoins) {
 	if coins == nil {
 		return
 	}
 	for _, coin := range *coins {
-		validatorCommission.WithLabelValues(coin.Denom).Set(coin.Amount.MustFloat64())
+		t.metrics.validatorCommission.WithLabelValues(t.labels(validator.labels(coin.Denom)...)...).Set(coin.Amount.MustFloat64())
 	}
 }
 
-func UpdateValidatorRewards(coins *types.DecCoins) {
+func (t *Target) UpdateValidatorRewards(validator Validator, coins *types.DecCoins) {
 	if coins == nil {
 		return
 	}
 	for _, coin := range *coins {
-		validatorRewards.WithLabelValues(coin.Denom).Set(coin.Amount.MustFloat64())
+		t.metrics.validatorRewards.WithLabelValues(t.labels(validator.labels(coin.Denom)...)...).Set(coin.Amount.MustFloat64())
+	}
+}
+
+// UpdateAccountBalances sets the account balances, removing the denoms not given anymore
+func (t *Target) UpdateAccountBalances(address string, alias string, coins types.Coins) {
+	var partialLabels = t.partialLabels()
+	partialLabels["address"] = address
+	t.metrics.accountBalance.DeletePartialMatch(partialLabels)
+	for _, coin := range coins {
+		t.metrics.accountBalance.WithLabelValues(t.labels(address, alias, coin.Denom)...).Set(intToFloat(coin.Amount))
 	}
 }
+
+// UpdateAccountSpendableBalances sets the account spendable balances, removing the denoms not given anymore
+func (t *Target) UpdateAccountSpendableBalances(address string, alias string, coins types.Coins) {
+	var partialLabels = t.partialLabels()
+	partialLabels["address"] = address
+	t.metrics.accountSpendableBalance.DeletePartialMatch(partialLabels)
+	for _, coin := range coins {
+		t.metrics.accountSpendableBalance.WithLabelValues(t.labels(address, alias, coin.Denom)...).Set(intToFloat(coin.Amount))
+	}
+}
+
+// intToFloat converts the big amounts to the metrics values
+func intToFloat(value types.Int) float64 {
+	return types.NewDecFromInt(value).MustFloat64()
+}
@@ -0,0 +1,25 @@
+package prometheus
+
+import (
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"testing"
+)
+
+func TestUpdateValidatorTokens(t *testing.T) {
+	var target = NewMetrics().NewTarget("evmos_9001-2", "test")
+	var validator = Validator{Moniker: "validator", Valcons: "evmosvalcons1test"}
+
+	// the 18 decimals chains amounts overflow uint64
+	tokens, _ := types.NewIntFromString("25000000000000000000000000")
+	minSelfDelegation, _ := types.NewIntFromString("1000000000000000000")
+	target.UpdateDelegatedTokens(validator, tokens)
+	target.UpdateMinSelfDelegation(validator, minSelfDelegation)
+
+	if got := testutil.ToFloat64(target.metrics.delegatedTokens); got != 25e24 {
+		t.Errorf("got delegated tokens %g, want 25e24", got)
+	}
+	if got := testutil.ToFloat64(target.metrics.minSelfDelegation); got != 1e18 {
+		t.Errorf("got min self delegation %g, want 1e18", got)
+	}
+}
@@ -0,0 +1,62 @@
+package prometheus
+
+import (
+	"fmt"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ProbeFunc updates synchronously the metrics of the target RPC node and validators
+type ProbeFunc func(metrics *Metrics, target string, validators []string) error
+
+// probeHandler handles the /probe?target=<rpc>&validator=<validator> requests, blackbox exporter style.
+// Each request exports its own metrics registry
+func probeHandler(probe ProbeFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var params = r.URL.Query()
+		var target = params.Get("target")
+		if target == "" {
+			http.Error(w, "target parameter is missing", http.StatusBadRequest)
+			return
+		}
+		// validators can be repeated or comma separated
+		var validators []string
+		for _, param := range params["validator"] {
+			for _, validator := range strings.Split(param, ",") {
+				if strings.TrimSpace(validator) != "" {
+					validators = append(validators, strings.TrimSpace(validator))
+				}
+			}
+		}
+
+		var probeSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "probe_success",
+			Help: "Whether the probe succeeded",
+		})
+		var probeDuration = prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "probe_duration_seconds",
+			Help: "Duration of the probe in seconds",
+		})
+
+		var registry = prometheus.NewRegistry()
+		var metrics = NewMetrics()
+		metrics.Register(registry)
+		registry.MustRegister(probeSuccess, probeDuration)
+
+		var start = time.Now()
+		err := probe(metrics, target, validators)
+		probeDuration.Set(time.Since(start).Seconds())
+		if err != nil {
+			log.Println(fmt.Sprintf("Probe of '%s' failed: %s", target, err.Error()))
+			probeSuccess.Set(0)
+		} else {
+			probeSuccess.Set(1)
+		}
+
+		promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	}
+}
@@ -2,6 +2,8 @@ package rpc
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	tmhttp "github.com/tendermint/tendermint/rpc/client/http"
 	coretypes "github.com/tendermint/tendermint/rpc/core/types"
 	ctypes "github.com/tendermint/tendermint/types"
@@ -17,8 +19,34 @@ func GetNodeInfo(client *tmhttp.HTTP) (*coretypes.ResultStatus, error) {
 	return resp, nil
 }
 
+// GetABCIInfo queries the RPC endpoint /abci_info to get the application info
+func GetABCIInfo(client *tmhttp.HTTP) (*coretypes.ResultABCIInfo, error) {
+	// perform the /abci_info request
+	resp, err := client.ABCIInfo(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetNetInfo queries the RPC endpoint /net_info to get the node P2P peers
+func GetNetInfo(client *tmhttp.HTTP) (*coretypes.ResultNetInfo, error) {
+	// perform the /net_info request
+	resp, err := client.NetInfo(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // GetValidators queries the RPC endpoint /validators
 func GetValidators(client *tmhttp.HTTP) (*[]*ctypes.Validator, error) {
+	return GetValidatorsAtHeight(client, nil)
+}
+
+// GetValidatorsAtHeight queries the RPC endpoint /validators at the given height (latest if nil).
+// The validators are decoded tolerating the consensus key types unknown to the exporter (ex. BLS12-381)
+func GetValidatorsAtHeight(client *tmhttp.HTTP, height *int64) (*[]*ctypes.Validator, error) {
 	var requestedHeight int64 = 0
 	var requestedPage = 1 // starts from 1
 	var perPage = 100
@@ -29,12 +57,16 @@ func GetValidators(client *tmhttp.HTTP) (*[]*ctypes.Validator, error) {
 
 	for !done {
 		// perform the /validators request
-		resp, err := client.Validators(context.Background(), nil, &requestedPage, &perPage)
+		resp, err := getRawValidators(client, height, requestedPage, perPage)
 		if err != nil {
 			return nil, err
 		}
 		// append the validators
-		for _, validator := range resp.Validators {
+		for _, rawValidator := range resp.Validators {
+			validator, err := rawValidator.decode()
+			if err != nil {
+				return nil, err
+			}
 			validators = append(validators, validator)
 		}
 
@@ -48,7 +80,7 @@ func GetValidators(client *tmhttp.HTTP) (*[]*ctypes.Validator, error) {
 		}
 
 		// if the validators count matches with the total, it is done
-		if len(validators) >= totalEntries {
+		if len(validators) >= totalEntries || len(resp.Validators) == 0 {
 			done = true
 			break
 		}
@@ -59,3 +91,26 @@ func GetValidators(client *tmhttp.HTTP) (*[]*ctypes.Validator, error) {
 
 	return &validators, nil
 }
+
+// GetBlock queries the RPC endpoint /block to get the block at the given height
+func GetBlock(client *tmhttp.HTTP, height int64) (*ctypes.Block, error) {
+	// perform the /block request
+	resp, err := client.Block(context.Background(), &height)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Block, nil
+}
+
+// GetBlockHeader queries the RPC endpoint /blockchain to get the header of the block at the given height
+func GetBlockHeader(client *tmhttp.HTTP, height int64) (*ctypes.Header, error) {
+	// perform the /blockchain request, lighter than the /block one
+	resp, err := client.BlockchainInfo(context.Background(), height, height)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.BlockMetas) == 0 {
+		return nil, errors.New(fmt.Sprintf("block %d not found", height))
+	}
+	return &resp.BlockMetas[0].Header, nil
+}
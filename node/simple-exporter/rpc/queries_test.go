@@ -0,0 +1,73 @@
+package rpc
+
+import (
+	"bytes"
+	ctypes "github.com/tendermint/tendermint/types"
+	"simple-exporter/testutil"
+	"testing"
+)
+
+func TestFixtures(t *testing.T) {
+	for _, chain := range testutil.Chains {
+		t.Run(chain.Name, func(t *testing.T) {
+			var client = testutil.NewFixtureRPC(t, chain)
+
+			status, err := GetNodeInfo(client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status.NodeInfo.Version != chain.CometBFT || status.NodeInfo.Network == "" {
+				t.Errorf("got node version '%s' on '%s', want '%s'", status.NodeInfo.Version, status.NodeInfo.Network, chain.CometBFT)
+			}
+			var height = status.SyncInfo.LatestBlockHeight
+			if height == 0 || status.SyncInfo.LatestBlockTime.IsZero() || status.SyncInfo.CatchingUp {
+				t.Errorf("got sync info %+v", status.SyncInfo)
+			}
+
+			abciInfo, err := GetABCIInfo(client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if abciInfo.Response.Data == "" || abciInfo.Response.Version == "" || abciInfo.Response.LastBlockHeight != height || len(abciInfo.Response.LastBlockAppHash) == 0 {
+				t.Errorf("got ABCI info %+v", abciInfo.Response)
+			}
+
+			validators, err := GetValidators(client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(*validators) < 3 {
+				t.Fatalf("got %d validators, want at least 3", len(*validators))
+			}
+			for _, validator := range *validators {
+				// the address is derived from the decoded public key
+				if !bytes.Equal(validator.PubKey.Address(), validator.Address) || validator.VotingPower <= 0 {
+					t.Errorf("got validator %s with key %s and power %d", validator.Address, validator.PubKey.Address(), validator.VotingPower)
+				}
+			}
+
+			block, err := GetBlock(client, height)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if block.Height != height || block.ChainID != status.NodeInfo.Network || len(block.Txs) != 1 {
+				t.Errorf("got block %d on '%s' with %d txs", block.Height, block.ChainID, len(block.Txs))
+			}
+			var signed, absent int
+			for _, signature := range block.LastCommit.Signatures {
+				switch signature.BlockIDFlag {
+				case ctypes.BlockIDFlagCommit:
+					signed++
+					if bytes.Equal((*validators)[0].Address, signature.ValidatorAddress) && len(signature.Signature) != 64 {
+						t.Errorf("got a %d bytes signature", len(signature.Signature))
+					}
+				case ctypes.BlockIDFlagAbsent:
+					absent++
+				}
+			}
+			if signed != len(*validators)-1 || absent != 1 || !bytes.Equal(block.ProposerAddress, (*validators)[0].Address) {
+				t.Errorf("got %d signed and %d absent votes, proposer %s", signed, absent, block.ProposerAddress)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/tendermint/tendermint/crypto"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	tmhttp "github.com/tendermint/tendermint/rpc/client/http"
+	jsonrpcclient "github.com/tendermint/tendermint/rpc/jsonrpc/client"
+	ctypes "github.com/tendermint/tendermint/types"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// QueryTimeout is the timeout of the raw RPC requests, not sent through the Tendermint client
+var QueryTimeout = 10 * time.Second
+
+// rawClients are the JSON-RPC clients of the raw requests, by Tendermint client, from NewClient to CloseClient
+var rawClients sync.Map
+
+// rawClient is the JSON-RPC client of the raw requests, sharing the connections of its Tendermint client
+type rawClient struct {
+	client     *jsonrpcclient.Client
+	httpClient *http.Client
+}
+
+// NewClient creates the Tendermint client of the RPC endpoint, along with the client of its raw requests.
+// It must be released with CloseClient
+func NewClient(remote string, timeout time.Duration) (*tmhttp.HTTP, error) {
+	httpClient, err := jsonrpcclient.DefaultHTTPClient(remote)
+	if err != nil {
+		return nil, err
+	}
+	httpClient.Timeout = timeout
+	client, err := tmhttp.NewWithClient(remote, "", httpClient)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := jsonrpcclient.NewWithHTTPClient(remote, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	rawClients.Store(client, &rawClient{client: raw, httpClient: httpClient})
+	return client, nil
+}
+
+// CloseClient releases the client created by NewClient, closing its idle connections
+func CloseClient(client *tmhttp.HTTP) {
+	if raw, ok := rawClients.LoadAndDelete(client); ok {
+		raw.(*rawClient).httpClient.CloseIdleConnections()
+	}
+}
+
+// rawResultValidators is the /validators result, with the validators public keys still encoded.
+// The Tendermint client fails to decode the whole result if a key type is not registered, ex. the BLS12-381 keys of the newer CometBFT chains
+type rawResultValidators struct {
+	BlockHeight int64          `json:"block_height"`
+	Validators  []rawValidator `json:"validators"`
+	Count       int            `json:"count"`
+	Total       int            `json:"total"`
+}
+
+type rawValidator struct {
+	Address          ctypes.Address  `json:"address"`
+	PubKey           json.RawMessage `json:"pub_key"`
+	VotingPower      int64           `json:"voting_power"`
+	ProposerPriority int64           `json:"proposer_priority"`
+}
+
+// decode decodes the validator, its public key being an unknownPubKey if its type is not registered
+func (v rawValidator) decode() (*ctypes.Validator, error) {
+	var validator = &ctypes.Validator{Address: v.Address, VotingPower: v.VotingPower, ProposerPriority: v.ProposerPriority}
+	err := tmjson.Unmarshal(v.PubKey, &validator.PubKey)
+	if err == nil {
+		return validator, nil
+	}
+
+	var encoded struct {
+		Type  string `json:"type"`
+		Value []byte `json:"value"`
+	}
+	if json.Unmarshal(v.PubKey, &encoded) != nil || encoded.Type == "" {
+		return nil, errors.New(fmt.Sprintf("cannot decode the public key of the validator %s (%s)", v.Address, err.Error()))
+	}
+	validator.PubKey = unknownPubKey{keyType: encoded.Type, key: encoded.Value, address: v.Address}
+	return validator, nil
+}
+
+// getRawValidators queries the RPC endpoint /validators, without decoding the validators public keys
+func getRawValidators(client *tmhttp.HTTP, height *int64, page int, perPage int) (*rawResultValidators, error) {
+	raw, err := getRawClient(client)
+	if err != nil {
+		return nil, err
+	}
+	var params = map[string]interface{}{
+		"page":     strconv.Itoa(page),
+		"per_page": strconv.Itoa(perPage),
+	}
+	if height != nil {
+		params["height"] = strconv.FormatInt(*height, 10)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), QueryTimeout)
+	defer cancel()
+	var result rawResultValidators
+	_, err = raw.Call(ctx, "validators", params, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// getRawClient returns the raw requests client of the Tendermint client.
+// The clients not created by NewClient get a new one, without connections reuse
+func getRawClient(client *tmhttp.HTTP) (*jsonrpcclient.Client, error) {
+	if raw, ok := rawClients.Load(client); ok {
+		return raw.(*rawClient).client, nil
+	}
+	httpClient, err := jsonrpcclient.DefaultHTTPClient(client.Remote())
+	if err != nil {
+		return nil, err
+	}
+	// the keep-alive connections would leak with the discarded client
+	httpClient.Transport.(*http.Transport).DisableKeepAlives = true
+	return jsonrpcclient.NewWithHTTPClient(client.Remote(), httpClient)
+}
+
+// unknownPubKey is a validator public key of a type unknown to the exporter.
+// It can't verify the signatures, but provides the key address and raw bytes to match the validators
+type unknownPubKey struct {
+	keyType string
+	key     []byte
+	address ctypes.Address // address reported by the node, not derivable without knowing the key type
+}
+
+func (k unknownPubKey) Address() crypto.Address {
+	return k.address
+}
+
+func (k unknownPubKey) Bytes() []byte {
+	return k.key
+}
+
+func (k unknownPubKey) VerifySignature(_ []byte, _ []byte) bool {
+	return false
+}
+
+func (k unknownPubKey) Equals(other crypto.PubKey) bool {
+	return k.Type() == other.Type() && bytes.Equal(k.Bytes(), other.Bytes())
+}
+
+func (k unknownPubKey) Type() string {
+	return k.keyType
+}
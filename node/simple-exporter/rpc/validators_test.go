@@ -0,0 +1,93 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	ctypes "github.com/tendermint/tendermint/types"
+	"simple-exporter/testutil"
+	"testing"
+	"time"
+)
+
+// blsKeyType is the BLS12-381 consensus key type of the newer CometBFT chains, not registered in the exporter Tendermint version
+const blsKeyType = "cometbft/PubKeyBls12_381"
+
+func TestValidatorsUnknownKeyType(t *testing.T) {
+	var edValidator = ctypes.NewValidator(ed25519.GenPrivKeyFromSecret([]byte("ed25519")).PubKey(), 20)
+	edJSON, err := tmjson.Marshal(edValidator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var blsKey = bytes.Repeat([]byte{0xb1}, 48)
+	var blsAddress = ctypes.Address(tmhash.SumTruncated(blsKey))
+	var blsJSON = fmt.Sprintf(`{"address":"%s","pub_key":{"type":"%s","value":"%s"},"voting_power":"10","proposer_priority":"-5"}`, blsAddress, blsKeyType, base64.StdEncoding.EncodeToString(blsKey))
+
+	var client = testutil.NewRPC(t, map[string]testutil.Method{
+		"validators": func(params map[string]json.RawMessage) (interface{}, error) {
+			return json.RawMessage(fmt.Sprintf(`{"block_height":"100","validators":[%s,%s],"count":"2","total":"2"}`, edJSON, blsJSON)), nil
+		},
+	})
+
+	// the Tendermint client can't decode the unknown key types
+	_, err = client.Validators(context.Background(), nil, nil, nil)
+	if err == nil {
+		t.Fatal("the Tendermint client decoded an unknown key type")
+	}
+
+	validators, err := GetValidators(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*validators) != 2 {
+		t.Fatalf("got %d validators, want 2", len(*validators))
+	}
+	var ed, bls = (*validators)[0], (*validators)[1]
+	if !ed.PubKey.Equals(edValidator.PubKey) || !bytes.Equal(ed.Address, edValidator.Address) || ed.VotingPower != 20 {
+		t.Errorf("got the ed25519 validator %s with key %s", ed.Address, ed.PubKey.Address())
+	}
+	// the unknown keys provide their type, raw bytes and reported address
+	if bls.PubKey.Type() != blsKeyType || !bytes.Equal(bls.PubKey.Bytes(), blsKey) || !bytes.Equal(bls.PubKey.Address(), blsAddress) || !bytes.Equal(bls.Address, blsAddress) {
+		t.Errorf("got the BLS validator %s with key %s of type %s", bls.Address, bls.PubKey.Address(), bls.PubKey.Type())
+	}
+	if bls.VotingPower != 10 || bls.ProposerPriority != -5 {
+		t.Errorf("got the BLS validator power %d and priority %d", bls.VotingPower, bls.ProposerPriority)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	var validator = ctypes.NewValidator(ed25519.GenPrivKeyFromSecret([]byte("ed25519")).PubKey(), 20)
+	var server = testutil.NewRPC(t, map[string]testutil.Method{
+		"validators": func(params map[string]json.RawMessage) (interface{}, error) {
+			return &coretypes.ResultValidators{BlockHeight: 100, Validators: []*ctypes.Validator{validator}, Count: 1, Total: 1}, nil
+		},
+	})
+	client, err := NewClient(server.Remote(), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the raw requests use the client created along with the Tendermint one
+	if _, ok := rawClients.Load(client); !ok {
+		t.Fatal("got no raw requests client for the new client")
+	}
+	validators, err := GetValidators(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*validators) != 1 || !bytes.Equal((*validators)[0].Address, validator.Address) {
+		t.Errorf("got validators %v", *validators)
+	}
+
+	// the closed clients don't keep their raw requests client
+	CloseClient(client)
+	if _, ok := rawClients.Load(client); ok {
+		t.Error("got the raw requests client of a closed client")
+	}
+}
@@ -0,0 +1,186 @@
+// Package testutil serves fake node endpoints to the tests, from handlers or from the recorded fixtures of each supported version
+package testutil
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	abcitypes "github.com/tendermint/tendermint/abci/types"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	tmhttp "github.com/tendermint/tendermint/rpc/client/http"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Chains are the fixtures of the supported versions, by Cosmos-Sdk version
+var Chains = []Chain{
+	{Name: "sdk-v0.45", SDK: "v0.45.9", CometBFT: "0.34.21"},
+	{Name: "sdk-v0.46", SDK: "v0.46.16", CometBFT: "0.34.29"},
+	{Name: "sdk-v0.47", SDK: "v0.47.10", CometBFT: "0.37.4"},
+	{Name: "sdk-v0.50", SDK: "v0.50.9", CometBFT: "0.38.11"},
+}
+
+// Chain identifies the fixtures of a chain version
+type Chain struct {
+	Name     string // fixtures folder in testdata
+	SDK      string // Cosmos-Sdk version, as reported by the node info query
+	CometBFT string // Tendermint/CometBFT version, as reported by /status
+}
+
+// Fixture returns the content of a fixture file of the chain
+func (c Chain) Fixture(t testing.TB, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(c.dir(), name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// dir returns the chain fixtures folder, next to this file whatever the package under test
+func (c Chain) dir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "testdata", c.Name)
+}
+
+// Method handles a JSON-RPC method of the fake node, from its params
+type Method func(params map[string]json.RawMessage) (interface{}, error)
+
+// NewRPC serves the given JSON-RPC methods like a node RPC endpoint, returning a client of it
+func NewRPC(t testing.TB, methods map[string]Method) *tmhttp.HTTP {
+	t.Helper()
+	return newServer(t, func(method string, params map[string]json.RawMessage) (json.RawMessage, *rpcError) {
+		handler, ok := methods[method]
+		if !ok {
+			return nil, &rpcError{Code: -32601, Message: "Method not found"}
+		}
+		result, err := handler(params)
+		if err != nil {
+			return nil, &rpcError{Code: -32603, Message: "Internal error", Data: err.Error()}
+		}
+		data, err := tmjson.Marshal(result)
+		if err != nil {
+			t.Errorf("cannot marshal the %s result: %s", method, err.Error())
+			return nil, &rpcError{Code: -32603, Message: "Internal error", Data: err.Error()}
+		}
+		return data, nil
+	})
+}
+
+// NewFixtureRPC replays the recorded responses of the chain like its node RPC endpoint, returning a client of it.
+// The responses are read from <method>.json, and from abci_query/<path>.json for the ABCI queries.
+// The queries without a fixture fail like the Cosmos-Sdk unknown query paths
+func NewFixtureRPC(t testing.TB, chain Chain) *tmhttp.HTTP {
+	t.Helper()
+	return NewFixtureRPCWithQueries(t, chain, nil)
+}
+
+// Query answers an ABCI query of the fake node from its request data, returning the response value
+type Query func(data []byte) ([]byte, error)
+
+// NewFixtureRPCWithQueries replays the recorded responses of the chain like NewFixtureRPC,
+// answering the given ABCI query paths with their handlers (ex. the modules without fixtures)
+func NewFixtureRPCWithQueries(t testing.TB, chain Chain, queries map[string]Query) *tmhttp.HTTP {
+	t.Helper()
+	return newServer(t, func(method string, params map[string]json.RawMessage) (json.RawMessage, *rpcError) {
+		var file = method + ".json"
+		if method == "abci_query" {
+			var path string
+			_ = json.Unmarshal(params["path"], &path)
+			if query, ok := queries[path]; ok {
+				return answerQuery(t, query, params)
+			}
+			file = filepath.Join("abci_query", strings.ReplaceAll(strings.TrimPrefix(path, "/"), "/", ".")+".json")
+		}
+		data, err := os.ReadFile(filepath.Join(chain.dir(), file))
+		if os.IsNotExist(err) && method == "abci_query" {
+			return json.RawMessage(`{"response":{"code":6,"log":"unknown query path: unknown request","codespace":"sdk"}}`), nil
+		}
+		if err != nil {
+			return nil, &rpcError{Code: -32601, Message: "Method not found", Data: err.Error()}
+		}
+		var recorded struct {
+			Result json.RawMessage `json:"result"`
+			Error  *rpcError       `json:"error"`
+		}
+		err = json.Unmarshal(data, &recorded)
+		if err != nil {
+			t.Errorf("invalid fixture %s: %s", file, err.Error())
+			return nil, &rpcError{Code: -32603, Message: "Internal error", Data: err.Error()}
+		}
+		return recorded.Result, recorded.Error
+	})
+}
+
+// answerQuery answers an ABCI query with its handler, the request data being hex encoded by the RPC client
+func answerQuery(t testing.TB, query Query, params map[string]json.RawMessage) (json.RawMessage, *rpcError) {
+	var data string
+	_ = json.Unmarshal(params["data"], &data)
+	request, err := hex.DecodeString(data)
+	if err != nil {
+		return nil, &rpcError{Code: -32602, Message: "Invalid params", Data: err.Error()}
+	}
+	value, err := query(request)
+	if err != nil {
+		return nil, &rpcError{Code: -32603, Message: "Internal error", Data: err.Error()}
+	}
+	result, err := tmjson.Marshal(&coretypes.ResultABCIQuery{Response: abcitypes.ResponseQuery{Value: value}})
+	if err != nil {
+		t.Errorf("cannot marshal the query result: %s", err.Error())
+		return nil, &rpcError{Code: -32603, Message: "Internal error", Data: err.Error()}
+	}
+	return result, nil
+}
+
+// Int64Param decodes an integer param, encoded as a string by the RPC client
+func Int64Param(params map[string]json.RawMessage, name string) int64 {
+	var value string
+	if json.Unmarshal(params[name], &value) != nil {
+		return 0
+	}
+	parsed, _ := strconv.ParseInt(value, 10, 64)
+	return parsed
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+// newServer serves the JSON-RPC requests with the given handler, answering with the request id
+func newServer(t testing.TB, handle func(method string, params map[string]json.RawMessage) (json.RawMessage, *rpcError)) *tmhttp.HTTP {
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     json.RawMessage            `json:"id"`
+			Method string                     `json:"method"`
+			Params map[string]json.RawMessage `json:"params"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result, rpcErr := handle(request.Method, request.Params)
+		if rpcErr != nil {
+			data, _ := json.Marshal(rpcErr)
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":%s}`, request.ID, data)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, request.ID, result)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := tmhttp.New(server.URL, "/websocket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}